@@ -0,0 +1,11 @@
+data "nginx_certificate" "example" {
+  path     = "/etc/ssl/certs/example.com.pem"
+  key_path = "/etc/ssl/private/example.com.key"
+}
+
+check "certificate" {
+  assert {
+    condition     = data.nginx_certificate.example.days_remaining > 14 && data.nginx_certificate.example.key_matches
+    error_message = "The certificate for ${data.nginx_certificate.example.common_name} expires in ${data.nginx_certificate.example.days_remaining} days or does not match its key."
+  }
+}
@@ -0,0 +1,8 @@
+data "nginx_config_test" "this" {}
+
+check "nginx_config" {
+  assert {
+    condition     = data.nginx_config_test.this.valid
+    error_message = data.nginx_config_test.this.output
+  }
+}
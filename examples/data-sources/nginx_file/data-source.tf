@@ -0,0 +1,7 @@
+data "nginx_file" "mime_types" {
+  path = "/etc/nginx/mime.types"
+}
+
+output "mime_types_sha256" {
+  value = data.nginx_file.mime_types.content_sha256
+}
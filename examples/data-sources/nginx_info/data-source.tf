@@ -0,0 +1,9 @@
+data "nginx_info" "this" {}
+
+output "nginx_version" {
+  value = data.nginx_info.this.version
+}
+
+output "has_http2" {
+  value = contains(data.nginx_info.this.modules, "http_v2_module")
+}
@@ -0,0 +1,5 @@
+data "nginx_listeners" "this" {}
+
+output "ports" {
+  value = distinct([for listener in data.nginx_listeners.this.listeners : listener.port])
+}
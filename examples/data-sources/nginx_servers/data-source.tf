@@ -0,0 +1,10 @@
+data "nginx_servers" "existing" {
+  generate_config = "nginx_site"
+}
+
+# Write the import and resource blocks for every server block that can be
+# brought under management.
+resource "local_file" "imports" {
+  filename = "${path.module}/imports.tf"
+  content  = data.nginx_servers.existing.resource_config
+}
@@ -0,0 +1,7 @@
+data "nginx_status" "this" {
+  stub_status_url = "http://127.0.0.1/nginx_status"
+}
+
+output "active_connections" {
+  value = data.nginx_status.this.active_connections
+}
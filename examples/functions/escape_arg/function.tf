@@ -0,0 +1,3 @@
+output "header" {
+  value = "add_header Content-Security-Policy ${provider::nginx::escape_arg("default-src 'self'")};"
+}
@@ -0,0 +1,3 @@
+output "formatted" {
+  value = provider::nginx::format("server { listen 80; server_name example.com; }")
+}
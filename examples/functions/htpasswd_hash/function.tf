@@ -0,0 +1,9 @@
+resource "random_string" "salt" {
+  length  = 8
+  special = false
+}
+
+output "htpasswd_line" {
+  value     = provider::nginx::htpasswd_hash("admin", var.admin_password, "apr1", random_string.salt.result)
+  sensitive = true
+}
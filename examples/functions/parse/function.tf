@@ -0,0 +1,7 @@
+locals {
+  config = provider::nginx::parse(file("${path.module}/nginx.conf"))
+}
+
+output "top_level_directives" {
+  value = [for directive in local.config : directive.name]
+}
@@ -0,0 +1,7 @@
+output "server_block" {
+  value = provider::nginx::render_server({
+    listen_port = 80
+    server_name = "example.com"
+    root        = "/var/www/example"
+  })
+}
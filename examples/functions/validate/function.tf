@@ -0,0 +1,10 @@
+locals {
+  snippet = file("${path.module}/snippet.conf")
+}
+
+check "snippet" {
+  assert {
+    condition     = length(provider::nginx::validate(local.snippet, "server")) == 0
+    error_message = join("\n", provider::nginx::validate(local.snippet, "server"))
+  }
+}
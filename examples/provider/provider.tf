@@ -1,3 +1,7 @@
-provider "scaffolding" {
-  # example configuration here
+# The host, username and password can also be set through the HOST,
+# USERNAME and PASSWORD environment variables.
+provider "nginx" {
+  host     = "203.0.113.10"
+  username = "deploy"
+  password = var.ssh_password
 }
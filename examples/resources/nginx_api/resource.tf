@@ -0,0 +1,17 @@
+resource "nginx_api" "example" {
+  api_name    = "example"
+  path        = "/etc/nginx/sites-enabled/api"
+  server_name = "api.example.com"
+  listen_port = 80
+
+  directives = [
+    {
+      name = "location"
+      args = ["/v1/"]
+      block = [
+        { name = "proxy_pass", args = ["http://127.0.0.1:8080/"] },
+        { name = "proxy_set_header", args = ["Host", "$host"] },
+      ]
+    },
+  ]
+}
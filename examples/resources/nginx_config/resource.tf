@@ -0,0 +1,13 @@
+# The content is written verbatim, tested with nginx -t and reloaded.
+resource "nginx_config" "status" {
+  config_name = "status"
+  path        = "/etc/nginx/sites-enabled/status"
+  content     = <<-EOT
+    server {
+        listen 127.0.0.1:80;
+        location = /nginx_status {
+            stub_status;
+        }
+    }
+  EOT
+}
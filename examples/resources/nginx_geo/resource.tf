@@ -0,0 +1,13 @@
+resource "nginx_geo" "office" {
+  geo_name = "office"
+  path     = "/etc/nginx/conf.d/office.conf"
+  variable = "$office_network"
+  default  = "0"
+
+  entries = [
+    {
+      value    = "1"
+      networks = ["192.0.2.0/24", "2001:db8::/32"]
+    },
+  ]
+}
@@ -0,0 +1,16 @@
+resource "nginx_proxy" "example" {
+  proxy_name  = "example"
+  path        = "/etc/nginx/sites-enabled/app"
+  server_name = "app.example.com"
+  listen_port = 80
+
+  directives = [
+    {
+      name = "location"
+      args = ["/app/"]
+      block = [
+        { name = "proxy_pass", args = ["http://127.0.0.1:3000/"] },
+      ]
+    },
+  ]
+}
@@ -0,0 +1,13 @@
+resource "nginx_redirect" "old_domain" {
+  redirect_name = "old_domain"
+  path          = "/etc/nginx/conf.d/old-domain.conf"
+  source_hosts  = ["old.example.com", "www.old.example.com"]
+  target        = "https://example.com"
+
+  rules = [
+    {
+      source = "/old-page"
+      target = "/new-page"
+    },
+  ]
+}
@@ -0,0 +1,19 @@
+resource "nginx_site" "example" {
+  site_name   = "example"
+  path        = "/etc/nginx/sites-enabled/example"
+  server_name = "example.com www.example.com"
+  listen_port = 80
+  root        = "/var/www/example"
+
+  security_headers = {
+    x_frame_options = "DENY"
+    referrer_policy = "strict-origin-when-cross-origin"
+  }
+
+  locations = [
+    {
+      path      = "/"
+      try_files = ["$uri", "$uri/", "/index.html"]
+    },
+  ]
+}
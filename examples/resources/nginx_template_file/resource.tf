@@ -0,0 +1,14 @@
+resource "nginx_template_file" "upstreams" {
+  path     = "/etc/nginx/conf.d/upstreams.conf"
+  template = <<-EOT
+    upstream app {
+    {{- range .servers }}
+        server {{ quote . }};
+    {{- end }}
+    }
+  EOT
+
+  vars = {
+    servers = ["10.0.0.11:8080", "10.0.0.12:8080"]
+  }
+}
@@ -0,0 +1,77 @@
+package nginx
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AccessModel describes the allow/deny rules of a server or location block.
+type AccessModel struct {
+	Satisfy types.String      `tfsdk:"satisfy"`
+	Rules   []AccessRuleModel `tfsdk:"rules"`
+}
+
+// AccessRuleModel describes a single allow or deny rule. Rules are rendered
+// in order, and NGINX stops at the first one that matches.
+type AccessRuleModel struct {
+	Action types.String `tfsdk:"action"`
+	Source types.String `tfsdk:"source"`
+}
+
+func accessAttribute(level string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Access control rules applied at the " + level + " level.",
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"satisfy": schema.StringAttribute{
+				MarkdownDescription: "Whether `all` or `any` of the access and auth checks must pass.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("all", "any"),
+				},
+			},
+			"rules": schema.ListNestedAttribute{
+				MarkdownDescription: "Ordered allow/deny rules. The first matching rule wins.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"action": schema.StringAttribute{
+							MarkdownDescription: "Either `allow` or `deny`.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("allow", "deny"),
+							},
+						},
+						"source": schema.StringAttribute{
+							MarkdownDescription: "An IP address, a CIDR network, `all` or `unix:`.",
+							Required:            true,
+							Validators: []validator.String{
+								cidrValidator{allowKeywords: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// accessDirectives renders the satisfy, allow and deny directives for an
+// access block. A nil block renders nothing.
+func accessDirectives(access *AccessModel) []*Directive {
+	if access == nil {
+		return nil
+	}
+
+	var directives []*Directive
+	if !access.Satisfy.IsNull() {
+		directives = append(directives, simpleDirective("satisfy", access.Satisfy.ValueString()))
+	}
+	for _, rule := range access.Rules {
+		directives = append(directives, simpleDirective(rule.Action.ValueString(), rule.Source.ValueString()))
+	}
+
+	return directives
+}
@@ -0,0 +1,49 @@
+package nginx
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// applyConfigFile writes content to the configuration file at path on the
+// host, checks the resulting configuration with nginx -t and reloads NGINX.
+// The output of nginx -t is part of the returned error.
+func applyConfigFile(client *ssh.Client, path string, content string) error {
+	quoted := shellQuote(path)
+	_, err := runCheckedChange(client, checkedChangeScript(quoted,
+		fmt.Sprintf("printf '%%s' %s | sudo tee %s > /dev/null", shellQuote(content), quoted)))
+	return err
+}
+
+// checkedChangeScript returns a shell script that runs change, a command
+// modifying the configuration file at quoted, then checks the configuration
+// with nginx -t and reloads NGINX. The file is backed up first and put back
+// when the change or the check fails; a file that did not exist before is
+// removed again.
+func checkedChangeScript(quoted string, change string) string {
+	restore := fmt.Sprintf("if [ -n \"$backup\" ]; then sudo mv \"$backup\" %[1]s; else sudo rm -f %[1]s; fi", quoted)
+
+	return fmt.Sprintf(
+		"backup=; if [ -f %[1]s ]; then backup=$(sudo mktemp) && sudo cp -p %[1]s \"$backup\" || exit 1; fi; "+
+			"if ! %[2]s; then %[3]s; exit 1; fi; "+
+			"if ! output=$(sudo nginx -t 2>&1); then %[3]s; echo \"$output\"; exit 1; fi; "+
+			"if [ -n \"$backup\" ]; then sudo rm -f \"$backup\"; fi; sudo nginx -s reload",
+		quoted, change, restore)
+}
+
+// runCheckedChange runs a script built by checkedChangeScript and returns
+// its output. On failure the output, such as that of nginx -t, is part of the
+// returned error.
+func runCheckedChange(client *ssh.Client, script string) ([]byte, error) {
+	output, err := runCommand(client, script)
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return output, fmt.Errorf("%w: %s", err, message)
+		}
+		return output, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,645 @@
+package nginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// directiveContext is a set of contexts a directive may appear in.
+type directiveContext uint32
+
+const (
+	contextMain directiveContext = 1 << iota
+	contextEvents
+	contextHTTP
+	contextServer
+	contextServerIf
+	contextLocation
+	contextLocationIf
+	contextLimitExcept
+	contextUpstream
+	contextStream
+	contextStreamServer
+	contextStreamUpstream
+)
+
+// Common combinations of contexts.
+const (
+	contextAny          = contextStreamUpstream<<1 - 1
+	httpContexts        = contextHTTP | contextServer | contextLocation
+	rewriteContexts     = contextServer | contextServerIf | contextLocation | contextLocationIf
+	streamContexts      = contextStream | contextStreamServer
+	upstreamContexts    = contextUpstream | contextStreamUpstream
+	httpServerContexts  = contextHTTP | contextServer
+	locationOnlyContext = contextLocation | contextLocationIf
+)
+
+// contextNames maps the context names accepted by the validate function
+// onto contexts.
+var contextNames = map[string]directiveContext{
+	"main":     contextMain,
+	"events":   contextEvents,
+	"http":     contextHTTP,
+	"server":   contextServer,
+	"location": contextLocation,
+	"upstream": contextUpstream,
+	"stream":   contextStream,
+}
+
+// directiveSpec describes where a directive may appear and the arguments it
+// takes, like the command definitions of the NGINX modules.
+type directiveSpec struct {
+	contexts directiveContext
+	minArgs  int
+	maxArgs  int // -1 for no limit
+	flag     bool
+	block    bool
+	// children is the context of the directives inside the block.
+	children directiveContext
+	// opaque blocks, such as map or types, hold entries rather than
+	// directives.
+	opaque bool
+}
+
+// unlimited stands for any number of arguments.
+const unlimited = -1
+
+func takes(contexts directiveContext, minArgs int, maxArgs int) directiveSpec {
+	return directiveSpec{contexts: contexts, minArgs: minArgs, maxArgs: maxArgs}
+}
+
+func flag(contexts directiveContext) directiveSpec {
+	return directiveSpec{contexts: contexts, minArgs: 1, maxArgs: 1, flag: true}
+}
+
+func block(contexts directiveContext, children directiveContext, minArgs int, maxArgs int) directiveSpec {
+	return directiveSpec{contexts: contexts, minArgs: minArgs, maxArgs: maxArgs, block: true, children: children}
+}
+
+func opaqueBlock(contexts directiveContext, minArgs int, maxArgs int) directiveSpec {
+	return directiveSpec{contexts: contexts, minArgs: minArgs, maxArgs: maxArgs, block: true, opaque: true}
+}
+
+// directiveCatalog lists the directives of the NGINX core and of the modules
+// commonly built into it. Directives whose meaning depends on the context,
+// such as server, have one spec per context.
+var directiveCatalog = map[string][]directiveSpec{
+	// Core
+	"user":                    {takes(contextMain, 1, 2)},
+	"worker_processes":        {takes(contextMain, 1, 1)},
+	"worker_rlimit_nofile":    {takes(contextMain, 1, 1)},
+	"worker_rlimit_core":      {takes(contextMain, 1, 1)},
+	"worker_cpu_affinity":     {takes(contextMain, 1, unlimited)},
+	"worker_priority":         {takes(contextMain, 1, 1)},
+	"worker_shutdown_timeout": {takes(contextMain, 1, 1)},
+	"working_directory":       {takes(contextMain, 1, 1)},
+	"pid":                     {takes(contextMain, 1, 1)},
+	"lock_file":               {takes(contextMain, 1, 1)},
+	"daemon":                  {flag(contextMain)},
+	"master_process":          {flag(contextMain)},
+	"pcre_jit":                {flag(contextMain)},
+	"timer_resolution":        {takes(contextMain, 1, 1)},
+	"env":                     {takes(contextMain, 1, 1)},
+	"thread_pool":             {takes(contextMain, 2, 3)},
+	"load_module":             {takes(contextMain, 1, 1)},
+	"ssl_engine":              {takes(contextMain, 1, 1)},
+	"include":                 {takes(contextAny, 1, 1)},
+	"error_log":               {takes(contextMain|httpContexts|streamContexts, 1, unlimited)},
+	"events":                  {block(contextMain, contextEvents, 0, 0)},
+	"http":                    {block(contextMain, contextHTTP, 0, 0)},
+	"stream":                  {block(contextMain, contextStream, 0, 0)},
+
+	// Events
+	"worker_connections":  {takes(contextEvents, 1, 1)},
+	"use":                 {takes(contextEvents, 1, 1)},
+	"multi_accept":        {flag(contextEvents)},
+	"accept_mutex":        {flag(contextEvents)},
+	"accept_mutex_delay":  {takes(contextEvents, 1, 1)},
+	"debug_connection":    {takes(contextEvents, 1, 1)},
+	"worker_aio_requests": {takes(contextEvents, 1, 1)},
+
+	// HTTP core
+	"server": {
+		block(contextHTTP, contextServer, 0, 0),
+		block(contextStream, contextStreamServer, 0, 0),
+		takes(upstreamContexts, 1, unlimited),
+	},
+	"server_name":                   {takes(contextServer|contextStreamServer, 1, unlimited)},
+	"listen":                        {takes(contextServer|contextStreamServer, 1, unlimited)},
+	"location":                      {block(contextServer|contextLocation, contextLocation, 1, 2)},
+	"limit_except":                  {block(contextLocation, contextLimitExcept, 1, unlimited)},
+	"internal":                      {takes(contextLocation, 0, 0)},
+	"root":                          {takes(httpContexts|contextLocationIf, 1, 1)},
+	"alias":                         {takes(contextLocation, 1, 1)},
+	"index":                         {takes(httpContexts, 1, unlimited)},
+	"try_files":                     {takes(contextServer|contextLocation, 2, unlimited)},
+	"error_page":                    {takes(httpContexts|contextLocationIf, 2, unlimited)},
+	"default_type":                  {takes(httpContexts, 1, 1)},
+	"types":                         {opaqueBlock(httpContexts, 0, 0)},
+	"types_hash_max_size":           {takes(httpContexts, 1, 1)},
+	"types_hash_bucket_size":        {takes(httpContexts, 1, 1)},
+	"server_tokens":                 {takes(httpContexts, 1, 1)},
+	"server_names_hash_max_size":    {takes(contextHTTP, 1, 1)},
+	"server_names_hash_bucket_size": {takes(contextHTTP, 1, 1)},
+	"variables_hash_max_size":       {takes(contextHTTP|contextStream, 1, 1)},
+	"variables_hash_bucket_size":    {takes(contextHTTP|contextStream, 1, 1)},
+	"client_max_body_size":          {takes(httpContexts, 1, 1)},
+	"client_body_buffer_size":       {takes(httpContexts, 1, 1)},
+	"client_body_timeout":           {takes(httpContexts, 1, 1)},
+	"client_body_temp_path":         {takes(httpContexts, 1, 4)},
+	"client_body_in_file_only":      {takes(httpContexts, 1, 1)},
+	"client_body_in_single_buffer":  {flag(httpContexts)},
+	"client_header_timeout":         {takes(httpServerContexts, 1, 1)},
+	"client_header_buffer_size":     {takes(httpServerContexts, 1, 1)},
+	"large_client_header_buffers":   {takes(httpServerContexts, 2, 2)},
+	"connection_pool_size":          {takes(httpServerContexts, 1, 1)},
+	"request_pool_size":             {takes(httpServerContexts, 1, 1)},
+	"keepalive_timeout":             {takes(httpContexts|contextUpstream, 1, 2)},
+	"keepalive_requests":            {takes(httpContexts|contextUpstream, 1, 1)},
+	"keepalive_time":                {takes(httpContexts|contextUpstream, 1, 1)},
+	"keepalive_disable":             {takes(httpContexts, 1, 2)},
+	"send_timeout":                  {takes(httpContexts, 1, 1)},
+	"sendfile":                      {flag(httpContexts | contextLocationIf)},
+	"sendfile_max_chunk":            {takes(httpContexts, 1, 1)},
+	"tcp_nopush":                    {flag(httpContexts)},
+	"tcp_nodelay":                   {flag(httpContexts | streamContexts)},
+	"aio":                           {takes(httpContexts, 1, 1)},
+	"aio_write":                     {flag(httpContexts)},
+	"directio":                      {takes(httpContexts, 1, 1)},
+	"directio_alignment":            {takes(httpContexts, 1, 1)},
+	"output_buffers":                {takes(httpContexts, 2, 2)},
+	"postpone_output":               {takes(httpContexts, 1, 1)},
+	"resolver":                      {takes(httpContexts|streamContexts, 1, unlimited)},
+	"resolver_timeout":              {takes(httpContexts|streamContexts, 1, 1)},
+	"limit_rate":                    {takes(httpContexts|contextLocationIf, 1, 1)},
+	"limit_rate_after":              {takes(httpContexts|contextLocationIf, 1, 1)},
+	"satisfy":                       {takes(httpContexts, 1, 1)},
+	"merge_slashes":                 {flag(httpServerContexts)},
+	"underscores_in_headers":        {flag(httpServerContexts)},
+	"ignore_invalid_headers":        {flag(httpServerContexts)},
+	"absolute_redirect":             {flag(httpContexts)},
+	"port_in_redirect":              {flag(httpContexts)},
+	"server_name_in_redirect":       {flag(httpContexts)},
+	"recursive_error_pages":         {flag(httpContexts)},
+	"log_not_found":                 {flag(httpContexts)},
+	"log_subrequest":                {flag(httpContexts)},
+	"msie_padding":                  {flag(httpContexts)},
+	"msie_refresh":                  {flag(httpContexts)},
+	"open_file_cache":               {takes(httpContexts, 1, 2)},
+	"open_file_cache_valid":         {takes(httpContexts, 1, 1)},
+	"open_file_cache_min_uses":      {takes(httpContexts, 1, 1)},
+	"open_file_cache_errors":        {flag(httpContexts)},
+	"reset_timedout_connection":     {flag(httpContexts)},
+	"lingering_close":               {takes(httpContexts, 1, 1)},
+	"lingering_time":                {takes(httpContexts, 1, 1)},
+	"lingering_timeout":             {takes(httpContexts, 1, 1)},
+	"chunked_transfer_encoding":     {flag(httpContexts)},
+	"etag":                          {flag(httpContexts)},
+	"if_modified_since":             {takes(httpContexts, 1, 1)},
+	"max_ranges":                    {takes(httpContexts, 1, 1)},
+	"disable_symlinks":              {takes(httpContexts, 1, 2)},
+	"subrequest_output_buffer_size": {takes(httpContexts, 1, 1)},
+	"auth_delay":                    {takes(httpContexts, 1, 1)},
+
+	// Rewrite
+	"return":                      {takes(rewriteContexts, 1, 2), takes(contextStreamServer, 1, 1)},
+	"rewrite":                     {takes(rewriteContexts, 2, 3)},
+	"set":                         {takes(rewriteContexts|contextStreamServer, 2, 2)},
+	"break":                       {takes(rewriteContexts, 0, 0)},
+	"if":                          {block(contextServer, contextServerIf, 1, unlimited), block(contextLocation, contextLocationIf, 1, unlimited)},
+	"rewrite_log":                 {flag(httpContexts | contextServerIf | contextLocationIf)},
+	"uninitialized_variable_warn": {flag(httpContexts | contextServerIf | contextLocationIf)},
+
+	// Access and authentication
+	"allow":                {takes(httpContexts|contextLimitExcept|streamContexts, 1, 1)},
+	"deny":                 {takes(httpContexts|contextLimitExcept|streamContexts, 1, 1)},
+	"auth_basic":           {takes(httpContexts|contextLimitExcept, 1, 1)},
+	"auth_basic_user_file": {takes(httpContexts|contextLimitExcept, 1, 1)},
+	"auth_request":         {takes(httpContexts, 1, 1)},
+	"auth_request_set":     {takes(httpContexts, 2, 2)},
+
+	// Static content
+	"autoindex":            {flag(httpContexts)},
+	"autoindex_exact_size": {flag(httpContexts)},
+	"autoindex_format":     {takes(httpContexts, 1, 1)},
+	"autoindex_localtime":  {flag(httpContexts)},
+	"random_index":         {flag(contextLocation)},
+	"empty_gif":            {takes(contextLocation, 0, 0)},
+	"stub_status":          {takes(contextServer|contextLocation, 0, 1)},
+	"slice":                {takes(httpContexts, 1, 1)},
+
+	// Headers
+	"add_header":  {takes(httpContexts|contextLocationIf, 2, 3)},
+	"add_trailer": {takes(httpContexts|contextLocationIf, 2, 3)},
+	"expires":     {takes(httpContexts|contextLocationIf, 1, 2)},
+
+	// Compression
+	"gzip":              {flag(httpContexts | contextLocationIf)},
+	"gzip_comp_level":   {takes(httpContexts, 1, 1)},
+	"gzip_types":        {takes(httpContexts, 1, unlimited)},
+	"gzip_min_length":   {takes(httpContexts, 1, 1)},
+	"gzip_proxied":      {takes(httpContexts, 1, unlimited)},
+	"gzip_vary":         {flag(httpContexts)},
+	"gzip_buffers":      {takes(httpContexts, 2, 2)},
+	"gzip_http_version": {takes(httpContexts, 1, 1)},
+	"gzip_disable":      {takes(httpContexts, 1, unlimited)},
+	"gzip_static":       {takes(httpContexts, 1, 1)},
+	"gunzip":            {flag(httpContexts)},
+	"brotli":            {flag(httpContexts | contextLocationIf)},
+	"brotli_comp_level": {takes(httpContexts, 1, 1)},
+	"brotli_types":      {takes(httpContexts, 1, unlimited)},
+	"brotli_static":     {takes(httpContexts, 1, 1)},
+	"brotli_min_length": {takes(httpContexts, 1, 1)},
+	"brotli_buffers":    {takes(httpContexts, 2, 2)},
+	"brotli_window":     {takes(httpContexts, 1, 1)},
+
+	// Logging
+	"access_log":          {takes(httpContexts|contextLocationIf|contextLimitExcept|streamContexts, 1, unlimited)},
+	"log_format":          {takes(contextHTTP|contextStream, 2, unlimited)},
+	"open_log_file_cache": {takes(httpContexts|streamContexts, 1, 4)},
+
+	// Charsets
+	"charset":          {takes(httpContexts|contextLocationIf, 1, 1)},
+	"source_charset":   {takes(httpContexts|contextLocationIf, 1, 1)},
+	"charset_types":    {takes(httpContexts, 1, unlimited)},
+	"override_charset": {flag(httpContexts | contextLocationIf)},
+	"charset_map":      {opaqueBlock(contextHTTP, 2, 2)},
+
+	// Variables
+	"map":                  {opaqueBlock(contextHTTP|contextStream, 2, 2)},
+	"map_hash_max_size":    {takes(contextHTTP|contextStream, 1, 1)},
+	"map_hash_bucket_size": {takes(contextHTTP|contextStream, 1, 1)},
+	"geo":                  {opaqueBlock(contextHTTP|contextStream, 1, 2)},
+	"split_clients":        {opaqueBlock(contextHTTP|contextStream, 2, 2)},
+	"set_real_ip_from":     {takes(httpContexts|streamContexts, 1, 1)},
+	"real_ip_header":       {takes(httpContexts, 1, 1)},
+	"real_ip_recursive":    {flag(httpContexts)},
+	"valid_referers":       {takes(contextServer|contextLocation, 1, unlimited)},
+
+	// Limits
+	"limit_conn_zone":      {takes(contextHTTP|contextStream, 2, 2)},
+	"limit_conn":           {takes(httpContexts|streamContexts, 2, 2)},
+	"limit_conn_status":    {takes(httpContexts, 1, 1)},
+	"limit_conn_log_level": {takes(httpContexts|streamContexts, 1, 1)},
+	"limit_conn_dry_run":   {flag(httpContexts | streamContexts)},
+	"limit_req_zone":       {takes(contextHTTP, 3, 4)},
+	"limit_req":            {takes(httpContexts, 1, 3)},
+	"limit_req_status":     {takes(httpContexts, 1, 1)},
+	"limit_req_log_level":  {takes(httpContexts, 1, 1)},
+	"limit_req_dry_run":    {flag(httpContexts)},
+
+	// Proxying
+	"proxy_pass":                     {takes(locationOnlyContext|contextLimitExcept|contextStreamServer, 1, 1)},
+	"proxy_set_header":               {takes(httpContexts, 2, 2)},
+	"proxy_redirect":                 {takes(httpContexts, 1, 2)},
+	"proxy_http_version":             {takes(httpContexts, 1, 1)},
+	"proxy_method":                   {takes(httpContexts, 1, 1)},
+	"proxy_set_body":                 {takes(httpContexts, 1, 1)},
+	"proxy_buffering":                {flag(httpContexts)},
+	"proxy_request_buffering":        {flag(httpContexts)},
+	"proxy_buffers":                  {takes(httpContexts, 2, 2)},
+	"proxy_buffer_size":              {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_busy_buffers_size":        {takes(httpContexts, 1, 1)},
+	"proxy_connect_timeout":          {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_read_timeout":             {takes(httpContexts, 1, 1)},
+	"proxy_send_timeout":             {takes(httpContexts, 1, 1)},
+	"proxy_timeout":                  {takes(streamContexts, 1, 1)},
+	"proxy_responses":                {takes(streamContexts, 1, 1)},
+	"proxy_protocol":                 {flag(streamContexts)},
+	"proxy_bind":                     {takes(httpContexts|streamContexts, 1, 2)},
+	"proxy_socket_keepalive":         {flag(httpContexts | streamContexts)},
+	"proxy_limit_rate":               {takes(httpContexts, 1, 1)},
+	"proxy_cache":                    {takes(httpContexts, 1, 1)},
+	"proxy_cache_path":               {takes(contextHTTP, 2, unlimited)},
+	"proxy_cache_key":                {takes(httpContexts, 1, 1)},
+	"proxy_cache_valid":              {takes(httpContexts, 1, unlimited)},
+	"proxy_cache_bypass":             {takes(httpContexts, 1, unlimited)},
+	"proxy_no_cache":                 {takes(httpContexts, 1, unlimited)},
+	"proxy_cache_use_stale":          {takes(httpContexts, 1, unlimited)},
+	"proxy_cache_methods":            {takes(httpContexts, 1, unlimited)},
+	"proxy_cache_min_uses":           {takes(httpContexts, 1, 1)},
+	"proxy_cache_lock":               {flag(httpContexts)},
+	"proxy_cache_lock_timeout":       {takes(httpContexts, 1, 1)},
+	"proxy_cache_lock_age":           {takes(httpContexts, 1, 1)},
+	"proxy_cache_revalidate":         {flag(httpContexts)},
+	"proxy_cache_background_update":  {flag(httpContexts)},
+	"proxy_cache_convert_head":       {flag(httpContexts)},
+	"proxy_cache_max_range_offset":   {takes(httpContexts, 1, 1)},
+	"proxy_hide_header":              {takes(httpContexts, 1, 1)},
+	"proxy_pass_header":              {takes(httpContexts, 1, 1)},
+	"proxy_ignore_headers":           {takes(httpContexts, 1, unlimited)},
+	"proxy_ignore_client_abort":      {flag(httpContexts)},
+	"proxy_intercept_errors":         {flag(httpContexts)},
+	"proxy_next_upstream":            {takes(httpContexts|streamContexts, 1, unlimited)},
+	"proxy_next_upstream_tries":      {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_next_upstream_timeout":    {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_pass_request_body":        {flag(httpContexts)},
+	"proxy_pass_request_headers":     {flag(httpContexts)},
+	"proxy_cookie_domain":            {takes(httpContexts, 1, 2)},
+	"proxy_cookie_path":              {takes(httpContexts, 1, 2)},
+	"proxy_cookie_flags":             {takes(httpContexts, 1, unlimited)},
+	"proxy_headers_hash_max_size":    {takes(httpContexts, 1, 1)},
+	"proxy_headers_hash_bucket_size": {takes(httpContexts, 1, 1)},
+	"proxy_max_temp_file_size":       {takes(httpContexts, 1, 1)},
+	"proxy_temp_file_write_size":     {takes(httpContexts, 1, 1)},
+	"proxy_temp_path":                {takes(httpContexts, 1, 4)},
+	"proxy_store":                    {takes(httpContexts, 1, 1)},
+	"proxy_store_access":             {takes(httpContexts, 1, 3)},
+	"proxy_force_ranges":             {flag(httpContexts)},
+	"proxy_ssl":                      {flag(streamContexts)},
+	"proxy_ssl_server_name":          {flag(httpContexts | streamContexts)},
+	"proxy_ssl_name":                 {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_ssl_verify":               {flag(httpContexts | streamContexts)},
+	"proxy_ssl_verify_depth":         {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_ssl_trusted_certificate":  {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_ssl_protocols":            {takes(httpContexts|streamContexts, 1, unlimited)},
+	"proxy_ssl_ciphers":              {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_ssl_certificate":          {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_ssl_certificate_key":      {takes(httpContexts|streamContexts, 1, 1)},
+	"proxy_ssl_session_reuse":        {flag(httpContexts | streamContexts)},
+
+	// FastCGI, uwsgi, SCGI, gRPC and memcached
+	"fastcgi_pass":                 {takes(locationOnlyContext, 1, 1)},
+	"fastcgi_param":                {takes(httpContexts, 2, 3)},
+	"fastcgi_index":                {takes(httpContexts, 1, 1)},
+	"fastcgi_split_path_info":      {takes(contextLocation, 1, 1)},
+	"fastcgi_connect_timeout":      {takes(httpContexts, 1, 1)},
+	"fastcgi_read_timeout":         {takes(httpContexts, 1, 1)},
+	"fastcgi_send_timeout":         {takes(httpContexts, 1, 1)},
+	"fastcgi_buffering":            {flag(httpContexts)},
+	"fastcgi_request_buffering":    {flag(httpContexts)},
+	"fastcgi_buffers":              {takes(httpContexts, 2, 2)},
+	"fastcgi_buffer_size":          {takes(httpContexts, 1, 1)},
+	"fastcgi_busy_buffers_size":    {takes(httpContexts, 1, 1)},
+	"fastcgi_keep_conn":            {flag(httpContexts)},
+	"fastcgi_intercept_errors":     {flag(httpContexts)},
+	"fastcgi_hide_header":          {takes(httpContexts, 1, 1)},
+	"fastcgi_pass_header":          {takes(httpContexts, 1, 1)},
+	"fastcgi_ignore_headers":       {takes(httpContexts, 1, unlimited)},
+	"fastcgi_next_upstream":        {takes(httpContexts, 1, unlimited)},
+	"fastcgi_cache":                {takes(httpContexts, 1, 1)},
+	"fastcgi_cache_path":           {takes(contextHTTP, 2, unlimited)},
+	"fastcgi_cache_key":            {takes(httpContexts, 1, 1)},
+	"fastcgi_cache_valid":          {takes(httpContexts, 1, unlimited)},
+	"fastcgi_cache_bypass":         {takes(httpContexts, 1, unlimited)},
+	"fastcgi_no_cache":             {takes(httpContexts, 1, unlimited)},
+	"fastcgi_cache_use_stale":      {takes(httpContexts, 1, unlimited)},
+	"fastcgi_cache_methods":        {takes(httpContexts, 1, unlimited)},
+	"fastcgi_cache_min_uses":       {takes(httpContexts, 1, 1)},
+	"fastcgi_cache_lock":           {flag(httpContexts)},
+	"fastcgi_temp_path":            {takes(httpContexts, 1, 4)},
+	"fastcgi_max_temp_file_size":   {takes(httpContexts, 1, 1)},
+	"fastcgi_catch_stderr":         {takes(httpContexts, 1, 1)},
+	"uwsgi_pass":                   {takes(locationOnlyContext, 1, 1)},
+	"uwsgi_param":                  {takes(httpContexts, 2, 3)},
+	"uwsgi_connect_timeout":        {takes(httpContexts, 1, 1)},
+	"uwsgi_read_timeout":           {takes(httpContexts, 1, 1)},
+	"uwsgi_send_timeout":           {takes(httpContexts, 1, 1)},
+	"uwsgi_buffering":              {flag(httpContexts)},
+	"uwsgi_buffers":                {takes(httpContexts, 2, 2)},
+	"uwsgi_buffer_size":            {takes(httpContexts, 1, 1)},
+	"scgi_pass":                    {takes(locationOnlyContext, 1, 1)},
+	"scgi_param":                   {takes(httpContexts, 2, 3)},
+	"scgi_connect_timeout":         {takes(httpContexts, 1, 1)},
+	"scgi_read_timeout":            {takes(httpContexts, 1, 1)},
+	"scgi_send_timeout":            {takes(httpContexts, 1, 1)},
+	"grpc_pass":                    {takes(locationOnlyContext, 1, 1)},
+	"grpc_set_header":              {takes(httpContexts, 2, 2)},
+	"grpc_hide_header":             {takes(httpContexts, 1, 1)},
+	"grpc_pass_header":             {takes(httpContexts, 1, 1)},
+	"grpc_connect_timeout":         {takes(httpContexts, 1, 1)},
+	"grpc_read_timeout":            {takes(httpContexts, 1, 1)},
+	"grpc_send_timeout":            {takes(httpContexts, 1, 1)},
+	"grpc_buffer_size":             {takes(httpContexts, 1, 1)},
+	"grpc_intercept_errors":        {flag(httpContexts)},
+	"grpc_next_upstream":           {takes(httpContexts, 1, unlimited)},
+	"grpc_ssl_server_name":         {flag(httpContexts)},
+	"grpc_ssl_name":                {takes(httpContexts, 1, 1)},
+	"grpc_ssl_verify":              {flag(httpContexts)},
+	"grpc_ssl_trusted_certificate": {takes(httpContexts, 1, 1)},
+	"grpc_ssl_certificate":         {takes(httpContexts, 1, 1)},
+	"grpc_ssl_certificate_key":     {takes(httpContexts, 1, 1)},
+	"memcached_pass":               {takes(locationOnlyContext, 1, 1)},
+
+	// Upstreams
+	"upstream": {
+		block(contextHTTP, contextUpstream, 1, 1),
+		block(contextStream, contextStreamUpstream, 1, 1),
+	},
+	"keepalive":  {takes(contextUpstream, 1, 1)},
+	"least_conn": {takes(upstreamContexts, 0, 0)},
+	"ip_hash":    {takes(contextUpstream, 0, 0)},
+	"hash":       {takes(upstreamContexts, 1, 2)},
+	"random":     {takes(upstreamContexts, 0, 2)},
+	"zone":       {takes(upstreamContexts, 1, 2)},
+
+	// TLS
+	"ssl_certificate":           {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_certificate_key":       {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_password_file":         {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_protocols":             {takes(httpServerContexts|streamContexts, 1, unlimited)},
+	"ssl_ciphers":               {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_prefer_server_ciphers": {flag(httpServerContexts | streamContexts)},
+	"ssl_session_cache":         {takes(httpServerContexts|streamContexts, 1, 2)},
+	"ssl_session_timeout":       {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_session_tickets":       {flag(httpServerContexts | streamContexts)},
+	"ssl_session_ticket_key":    {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_dhparam":               {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_ecdh_curve":            {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_conf_command":          {takes(httpServerContexts|streamContexts, 2, 2)},
+	"ssl_stapling":              {flag(httpServerContexts)},
+	"ssl_stapling_verify":       {flag(httpServerContexts)},
+	"ssl_stapling_file":         {takes(httpServerContexts, 1, 1)},
+	"ssl_stapling_responder":    {takes(httpServerContexts, 1, 1)},
+	"ssl_trusted_certificate":   {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_client_certificate":    {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_verify_client":         {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_verify_depth":          {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_crl":                   {takes(httpServerContexts|streamContexts, 1, 1)},
+	"ssl_ocsp":                  {takes(httpServerContexts, 1, 1)},
+	"ssl_buffer_size":           {takes(httpServerContexts, 1, 1)},
+	"ssl_early_data":            {flag(httpServerContexts)},
+	"ssl_reject_handshake":      {flag(httpServerContexts)},
+	"ssl_handshake_timeout":     {takes(streamContexts, 1, 1)},
+	"ssl_preread":               {flag(streamContexts)},
+
+	// HTTP/2 and HTTP/3
+	"http2":                        {flag(httpServerContexts)},
+	"http2_max_concurrent_streams": {takes(httpServerContexts, 1, 1)},
+	"http2_chunk_size":             {takes(httpContexts, 1, 1)},
+	"http2_body_preread_size":      {takes(httpServerContexts, 1, 1)},
+	"http2_recv_buffer_size":       {takes(contextHTTP, 1, 1)},
+	"http3":                        {flag(httpServerContexts)},
+	"http3_hq":                     {flag(httpServerContexts)},
+	"http3_max_concurrent_streams": {takes(httpServerContexts, 1, 1)},
+	"http3_stream_buffer_size":     {takes(httpServerContexts, 1, 1)},
+	"quic_retry":                   {flag(httpServerContexts)},
+	"quic_gso":                     {flag(httpServerContexts)},
+	"quic_host_key":                {takes(httpServerContexts, 1, 1)},
+
+	// Filters and other common modules
+	"sub_filter":               {takes(httpContexts, 2, 2)},
+	"sub_filter_once":          {flag(httpContexts)},
+	"sub_filter_types":         {takes(httpContexts, 1, unlimited)},
+	"sub_filter_last_modified": {flag(httpContexts)},
+	"ssi":                      {flag(httpContexts | contextLocationIf)},
+	"ssi_types":                {takes(httpContexts, 1, unlimited)},
+	"mirror":                   {takes(httpContexts, 1, 1)},
+	"mirror_request_body":      {flag(httpContexts)},
+	"secure_link":              {takes(httpContexts, 1, 1)},
+	"secure_link_md5":          {takes(httpContexts, 1, 1)},
+	"secure_link_secret":       {takes(contextLocation, 1, 1)},
+	"dav_methods":              {takes(httpContexts, 1, unlimited)},
+	"dav_access":               {takes(httpContexts, 1, 3)},
+	"create_full_put_path":     {flag(httpContexts)},
+	"min_delete_depth":         {takes(httpContexts, 1, 1)},
+
+	// Stream core
+	"preread_buffer_size":    {takes(streamContexts, 1, 1)},
+	"preread_timeout":        {takes(streamContexts, 1, 1)},
+	"proxy_protocol_timeout": {takes(streamContexts, 1, 1)},
+}
+
+// catalogProblem is a directive the catalog rejects.
+type catalogProblem struct {
+	directive *Directive
+	message   string
+	// unknown is set when the directive is not in the catalog, which may
+	// only mean that it comes from a third-party module.
+	unknown bool
+}
+
+// checkDirectives checks directives, found in context, and their children
+// against the directive catalog.
+func checkDirectives(directives []*Directive, context directiveContext) []catalogProblem {
+	var problems []catalogProblem
+
+	for _, d := range directives {
+		if d.IsComment {
+			continue
+		}
+
+		specs, known := directiveCatalog[d.Name]
+		if !known {
+			problems = append(problems, catalogProblem{d, fmt.Sprintf("unknown directive %q", d.Name), true})
+			continue
+		}
+
+		var spec *directiveSpec
+		for i := range specs {
+			if specs[i].contexts&context != 0 {
+				spec = &specs[i]
+				break
+			}
+		}
+		if spec == nil {
+			problems = append(problems, catalogProblem{d, fmt.Sprintf("%q directive is not allowed here", d.Name), false})
+			continue
+		}
+
+		if message := spec.check(d); message != "" {
+			problems = append(problems, catalogProblem{d, message, false})
+			continue
+		}
+
+		if spec.block && !spec.opaque && d.Raw == "" {
+			problems = append(problems, checkDirectives(d.Block, spec.children)...)
+		}
+	}
+
+	return problems
+}
+
+// check returns why d does not match the spec, or an empty string.
+func (s directiveSpec) check(d *Directive) string {
+	if s.block && !d.IsBlock {
+		return fmt.Sprintf("directive %q has no opening \"{\"", d.Name)
+	}
+	if !s.block && d.IsBlock {
+		return fmt.Sprintf("%q directive does not take a block", d.Name)
+	}
+	if len(d.Args) < s.minArgs || (s.maxArgs != unlimited && len(d.Args) > s.maxArgs) {
+		return fmt.Sprintf("invalid number of arguments in %q directive", d.Name)
+	}
+	if s.flag {
+		if value := strings.ToLower(d.Args[0]); value != "on" && value != "off" {
+			return fmt.Sprintf("invalid value %q in %q directive, it must be \"on\" or \"off\"", d.Args[0], d.Name)
+		}
+	}
+	return ""
+}
+
+// String formats the problem the way NGINX reports configuration errors,
+// with the line when the directive was parsed from text.
+func (p catalogProblem) String() string {
+	if p.directive.Line > 0 {
+		return fmt.Sprintf("%s in line %d", p.message, p.directive.Line)
+	}
+	return p.message
+}
+
+// validateExtraDirectives checks extra directives, rendered in context,
+// against the directive catalog.
+func validateExtraDirectives(extras []ExtraDirectiveModel, context directiveContext, attributePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, d := range extraDirectives(extras) {
+		for _, problem := range checkDirectives([]*Directive{d}, context) {
+			addCatalogProblem(&diags, attributePath.AtListIndex(i), problem)
+		}
+	}
+	return diags
+}
+
+// validateContent checks configuration set by the user, which is included in
+// the http context, against the directive catalog.
+func validateContent(content types.String, attributePath path.Path) diag.Diagnostics {
+	if content.IsNull() || content.IsUnknown() {
+		return nil
+	}
+	return validateConfigText(content.ValueString(), contextHTTP, attributePath)
+}
+
+// validateConfigText parses config and checks it, as included in context,
+// against the directive catalog.
+func validateConfigText(config string, context directiveContext, attributePath path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	directives, err := parseConfig(config)
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid Configuration",
+			fmt.Sprintf("Failed to parse the configuration: %s", err),
+		)
+		return diags
+	}
+
+	for _, problem := range checkDirectives(directives, context) {
+		addCatalogProblem(&diags, attributePath, problem)
+	}
+	return diags
+}
+
+// addCatalogProblem reports a problem found by the directive catalog.
+// Unknown directives only give a warning, since they may come from a
+// third-party module.
+func addCatalogProblem(diags *diag.Diagnostics, attributePath path.Path, problem catalogProblem) {
+	if problem.unknown {
+		diags.AddAttributeWarning(
+			attributePath,
+			"Unknown Directive",
+			fmt.Sprintf("The configuration uses %s. NGINX rejects it unless a module providing it is loaded.", problem),
+		)
+		return
+	}
+
+	diags.AddAttributeError(
+		attributePath,
+		"Invalid Directive",
+		fmt.Sprintf("NGINX would reject the configuration: %s.", problem),
+	)
+}
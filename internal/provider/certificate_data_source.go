@@ -0,0 +1,324 @@
+package nginx
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CertificateDataSource{}
+
+func NewCertificateDataSource() datasource.DataSource {
+	return &CertificateDataSource{}
+}
+
+// CertificateDataSource defines the data source implementation.
+type CertificateDataSource struct {
+	client interface{} // Use interface{} to accept SSH client passed from provider.go
+}
+
+// CertificateDataSourceModel describes the data source data model.
+type CertificateDataSourceModel struct {
+	Path              types.String   `tfsdk:"path"`
+	KeyPath           types.String   `tfsdk:"key_path"`
+	Subject           types.String   `tfsdk:"subject"`
+	CommonName        types.String   `tfsdk:"common_name"`
+	SANs              []types.String `tfsdk:"sans"`
+	Issuer            types.String   `tfsdk:"issuer"`
+	SerialNumber      types.String   `tfsdk:"serial_number"`
+	NotBefore         types.String   `tfsdk:"not_before"`
+	NotAfter          types.String   `tfsdk:"not_after"`
+	DaysRemaining     types.Int64    `tfsdk:"days_remaining"`
+	FingerprintSHA256 types.String   `tfsdk:"fingerprint_sha256"`
+	ChainLength       types.Int64    `tfsdk:"chain_length"`
+	ChainComplete     types.Bool     `tfsdk:"chain_complete"`
+	ChainError        types.String   `tfsdk:"chain_error"`
+	KeyMatches        types.Bool     `tfsdk:"key_matches"`
+	Id                types.String   `tfsdk:"id"`
+}
+
+func (d *CertificateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certificate"
+}
+
+func (d *CertificateDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Inspects a PEM certificate on the host, such as the one an `ssl_certificate` directive " +
+			"points to. The file is parsed by the provider, so nothing beyond `cat` is needed on the host.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The absolute path of the PEM file holding the certificate, optionally followed " +
+					"by its intermediate certificates.",
+				Required: true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
+			},
+			"key_path": schema.StringAttribute{
+				MarkdownDescription: "The absolute path of the PEM private key to check against the certificate. " +
+					"The key is only read to compute `key_matches` and is never stored.",
+				Optional: true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
+			},
+			"subject": schema.StringAttribute{
+				MarkdownDescription: "The distinguished name of the subject.",
+				Computed:            true,
+			},
+			"common_name": schema.StringAttribute{
+				MarkdownDescription: "The common name of the subject.",
+				Computed:            true,
+			},
+			"sans": schema.ListAttribute{
+				MarkdownDescription: "The subject alternative names: DNS names, IP addresses, email addresses and URIs.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"issuer": schema.StringAttribute{
+				MarkdownDescription: "The distinguished name of the issuer.",
+				Computed:            true,
+			},
+			"serial_number": schema.StringAttribute{
+				MarkdownDescription: "The serial number, in hexadecimal.",
+				Computed:            true,
+			},
+			"not_before": schema.StringAttribute{
+				MarkdownDescription: "The start of the validity period, in RFC 3339 format.",
+				Computed:            true,
+			},
+			"not_after": schema.StringAttribute{
+				MarkdownDescription: "The end of the validity period, in RFC 3339 format.",
+				Computed:            true,
+			},
+			"days_remaining": schema.Int64Attribute{
+				MarkdownDescription: "The number of whole days until the certificate expires, negative once it has expired.",
+				Computed:            true,
+			},
+			"fingerprint_sha256": schema.StringAttribute{
+				MarkdownDescription: "The SHA-256 fingerprint of the certificate, in hexadecimal.",
+				Computed:            true,
+			},
+			"chain_length": schema.Int64Attribute{
+				MarkdownDescription: "The number of certificates in the file.",
+				Computed:            true,
+			},
+			"chain_complete": schema.BoolAttribute{
+				MarkdownDescription: "Whether the certificates in the file chain up to a root trusted by the system " +
+					"the provider runs on.",
+				Computed: true,
+			},
+			"chain_error": schema.StringAttribute{
+				MarkdownDescription: "Why the chain is not complete, or null when it is.",
+				Computed:            true,
+			},
+			"key_matches": schema.BoolAttribute{
+				MarkdownDescription: "Whether the private key at `key_path` belongs to the certificate. Null when " +
+					"`key_path` is not set.",
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The path of the certificate.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *CertificateDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ssh.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *CertificateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CertificateDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	certPath := data.Path.ValueString()
+	certPEM, found, err := readRemoteFile(d.client.(*ssh.Client), certPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read %s: %s", certPath, err),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError(
+			"Certificate Not Found",
+			fmt.Sprintf("The file at path '%s' does not exist.", certPath),
+		)
+		return
+	}
+
+	chain, err := parseCertificateChain([]byte(certPEM))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Certificate",
+			fmt.Sprintf("Failed to parse %s: %s", certPath, err),
+		)
+		return
+	}
+	leaf := chain[0]
+	now := time.Now()
+
+	data.Subject = types.StringValue(leaf.Subject.String())
+	data.CommonName = types.StringValue(leaf.Subject.CommonName)
+	data.SANs = nonNullStringList(certificateSANs(leaf))
+	data.Issuer = types.StringValue(leaf.Issuer.String())
+	data.SerialNumber = types.StringValue(leaf.SerialNumber.Text(16))
+	data.NotBefore = types.StringValue(leaf.NotBefore.UTC().Format(time.RFC3339))
+	data.NotAfter = types.StringValue(leaf.NotAfter.UTC().Format(time.RFC3339))
+	data.DaysRemaining = types.Int64Value(daysRemaining(leaf, now))
+	fingerprint := sha256.Sum256(leaf.Raw)
+	data.FingerprintSHA256 = types.StringValue(hex.EncodeToString(fingerprint[:]))
+	data.ChainLength = types.Int64Value(int64(len(chain)))
+
+	data.ChainComplete = types.BoolValue(true)
+	data.ChainError = types.StringNull()
+	if err := verifyCertificateChain(chain, now); err != nil {
+		data.ChainComplete = types.BoolValue(false)
+		data.ChainError = types.StringValue(err.Error())
+	}
+
+	data.KeyMatches = types.BoolNull()
+	if !data.KeyPath.IsNull() {
+		keyPath := data.KeyPath.ValueString()
+		keyPEM, found, err := readRemoteFile(d.client.(*ssh.Client), keyPath)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Command Execution Error",
+				fmt.Sprintf("Failed to read %s: %s", keyPath, err),
+			)
+			return
+		}
+		if !found {
+			resp.Diagnostics.AddError(
+				"Private Key Not Found",
+				fmt.Sprintf("The file at path '%s' does not exist.", keyPath),
+			)
+			return
+		}
+
+		// X509KeyPair checks that the public key of the leaf certificate
+		// belongs to the private key
+		_, err = tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		data.KeyMatches = types.BoolValue(err == nil)
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Private Key Mismatch",
+				fmt.Sprintf("The private key at %s cannot be used with the certificate at %s: %s", keyPath, certPath, err),
+			)
+		}
+	}
+
+	data.Id = types.StringValue(certPath)
+
+	tflog.Trace(ctx, fmt.Sprintf("Read certificate %s expiring in %d days", certPath, data.DaysRemaining.ValueInt64()))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseCertificateChain parses the certificates in a PEM file, the leaf
+// certificate first. Blocks other than certificates are skipped.
+func parseCertificateChain(data []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", len(chain)+1, err)
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return chain, nil
+}
+
+// verifyCertificateChain checks that the leaf certificate chains up to a
+// system root through the intermediates that follow it.
+func verifyCertificateChain(chain []*x509.Certificate, now time.Time) error {
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		return fmt.Errorf("failed to load the system roots: %w", err)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// certificateSANs returns the subject alternative names of cert.
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+// daysRemaining returns the number of whole days from now until cert
+// expires, rounded down so that an expired certificate gives -1 or less.
+func daysRemaining(cert *x509.Certificate, now time.Time) int64 {
+	return int64(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+}
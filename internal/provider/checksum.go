@@ -0,0 +1,181 @@
+package nginx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// remoteFileInfo describes a file on the host.
+type remoteFileInfo struct {
+	SHA256 string
+	Size   int64
+	Mtime  time.Time
+}
+
+// serverAttributes are the structured attributes the server block resources
+// render their configuration from.
+var serverAttributes = []string{"server_name", "listen_port", "root", "access", "headers", "security_headers", "directives"}
+
+// contentAttribute returns the content attribute of a resource rendered from
+// the structured attributes named by structured. Content set in the
+// configuration replaces the rendered configuration, so it conflicts with
+// them.
+func contentAttribute(structured []string) schema.StringAttribute {
+	conflicts := make([]path.Expression, 0, len(structured))
+	for _, name := range structured {
+		conflicts = append(conflicts, path.MatchRoot(name))
+	}
+
+	return schema.StringAttribute{
+		MarkdownDescription: "The configuration to write to the file verbatim, instead of rendering it from the " +
+			"structured attributes, which cannot be set alongside it. The content is checked against the " +
+			"directive catalog at plan time. When not set, it holds the rendered configuration if `store_content` " +
+			"is set.",
+		Optional: true,
+		Computed: true,
+		Validators: []validator.String{
+			stringvalidator.ConflictsWith(conflicts...),
+		},
+	}
+}
+
+func storeContentAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether to keep the rendered configuration in `content`. Drift is detected through " +
+			"`content_sha256` either way, so this is only needed to see the configuration in state. Defaults to `false`.",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+	}
+}
+
+func contentSHA256Attribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "The SHA-256 checksum of the file on the host. A checksum that no longer matches " +
+			"the rendered configuration plans a rewrite of the file.",
+		Computed: true,
+	}
+}
+
+func sizeAttribute() schema.Int64Attribute {
+	return schema.Int64Attribute{
+		MarkdownDescription: "The size of the file on the host, in bytes.",
+		Computed:            true,
+	}
+}
+
+func mtimeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "The modification time of the file on the host, in RFC 3339 format.",
+		Computed:            true,
+	}
+}
+
+// contentChecksum returns the hex-encoded SHA-256 checksum of content, as
+// sha256sum prints it.
+func contentChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// fileAttributes returns the content_sha256, size and mtime attributes for
+// info, or null values when the file does not exist.
+func fileAttributes(info remoteFileInfo, found bool) (types.String, types.Int64, types.String) {
+	if !found {
+		return types.StringNull(), types.Int64Null(), types.StringNull()
+	}
+	return types.StringValue(info.SHA256), types.Int64Value(info.Size), types.StringValue(info.Mtime.UTC().Format(time.RFC3339))
+}
+
+// fileContent returns the configuration to write: the content set in the
+// configuration, verbatim, or else the one render builds from the structured
+// attributes.
+func fileContent(ctx context.Context, config tfsdk.Config, render func() string) (string, diag.Diagnostics) {
+	var content types.String
+	diags := config.GetAttribute(ctx, path.Root("content"), &content)
+	if diags.HasError() || content.IsNull() || content.IsUnknown() {
+		return render(), diags
+	}
+	return content.ValueString(), diags
+}
+
+// appliedContent returns the content attribute to store after writing
+// rendered: the planned value when it is known, otherwise the rendered
+// configuration if store is set.
+func appliedContent(planned types.String, store types.Bool, rendered string) types.String {
+	if !planned.IsUnknown() {
+		return planned
+	}
+	if store.ValueBool() {
+		return types.StringValue(rendered)
+	}
+	return types.StringNull()
+}
+
+// planFile plans the content, content_sha256 and size attributes of a file
+// that will hold rendered. When nothing else changes but the checksum in
+// state differs from the rendered configuration, such as after the file was
+// edited on the host, the new checksum is planned so that the file gets
+// rewritten.
+func planFile(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, rendered string) {
+	var content types.String
+	var store types.Bool
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("content"), &content)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("store_content"), &store)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("content"), &configured)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Content carried over from state goes stale once it no longer matches
+	// the rendered configuration, such as after content is removed from the
+	// configuration to render the file again
+	if content.IsUnknown() || (configured.IsNull() && !content.IsNull() && content.ValueString() != rendered) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), appliedContent(types.StringUnknown(), store, rendered))...)
+	}
+
+	planChecksum(ctx, resp, rendered)
+}
+
+// planChecksum plans the content_sha256 and size attributes of a file that
+// will hold rendered, and leaves mtime unknown when the file gets rewritten.
+// Resources that always keep the rendered configuration in content use it
+// without planFile.
+func planChecksum(ctx context.Context, resp *resource.ModifyPlanResponse, rendered string) {
+	expected := contentChecksum(rendered)
+
+	var planned types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("content_sha256"), &planned)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A known checksum in the plan is the one from state, carried over
+	// because nothing else changed
+	if !planned.IsUnknown() {
+		if planned.ValueString() == expected {
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("mtime"), types.StringUnknown())...)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_sha256"), expected)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("size"), int64(len(rendered)))...)
+}
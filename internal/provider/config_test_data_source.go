@@ -0,0 +1,258 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ConfigTestDataSource{}
+
+// testOutputMarker separates the configuration dump from the messages of
+// nginx -t in the command run by the config test data source, and is
+// followed by the exit status at the end.
+const testOutputMarker = "--- nginx -t ---"
+
+// testMessagePattern matches a message logged by nginx -t, such as
+// `nginx: [warn] duplicate MIME type "text/html" in /etc/nginx/nginx.conf:30`.
+var testMessagePattern = regexp.MustCompile(`^(?:nginx: )?\[(\w+)\] (.*?)(?: in (.+):(\d+))?$`)
+
+// testErrorLevels are the log levels that make the configuration test fail.
+var testErrorLevels = map[string]bool{
+	"emerg": true,
+	"alert": true,
+	"crit":  true,
+	"error": true,
+}
+
+func NewConfigTestDataSource() datasource.DataSource {
+	return &ConfigTestDataSource{}
+}
+
+// ConfigTestDataSource defines the data source implementation.
+type ConfigTestDataSource struct {
+	client interface{} // Use interface{} to accept SSH client passed from provider.go
+}
+
+// ConfigTestDataSourceModel describes the data source data model.
+type ConfigTestDataSourceModel struct {
+	Dump            types.Bool               `tfsdk:"dump"`
+	Valid           types.Bool               `tfsdk:"valid"`
+	Errors          []ConfigTestMessageModel `tfsdk:"errors"`
+	Warnings        []ConfigTestMessageModel `tfsdk:"warnings"`
+	Output          types.String             `tfsdk:"output"`
+	EffectiveConfig types.String             `tfsdk:"effective_config"`
+	Id              types.String             `tfsdk:"id"`
+}
+
+// ConfigTestMessageModel describes a message logged by nginx -t.
+type ConfigTestMessageModel struct {
+	Level   types.String `tfsdk:"level"`
+	Message types.String `tfsdk:"message"`
+	File    types.String `tfsdk:"file"`
+	Line    types.Int64  `tfsdk:"line"`
+}
+
+func (d *ConfigTestDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_test"
+}
+
+func (d *ConfigTestDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	messageAttributes := schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"level": schema.StringAttribute{
+				MarkdownDescription: "The log level, such as `emerg` or `warn`.",
+				Computed:            true,
+			},
+			"message": schema.StringAttribute{
+				MarkdownDescription: "The message, without the file and line.",
+				Computed:            true,
+			},
+			"file": schema.StringAttribute{
+				MarkdownDescription: "The configuration file the message refers to, if any.",
+				Computed:            true,
+			},
+			"line": schema.Int64Attribute{
+				MarkdownDescription: "The line in `file` the message refers to, if any.",
+				Computed:            true,
+			},
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Tests the NGINX configuration on the host with `nginx -t`. An invalid configuration " +
+			"is not an error, so that it can be checked in a `precondition` instead.",
+
+		Attributes: map[string]schema.Attribute{
+			"dump": schema.BoolAttribute{
+				MarkdownDescription: "Whether to run `nginx -T` and return the effective configuration in " +
+					"`effective_config`. Defaults to `false`.",
+				Optional: true,
+			},
+			"valid": schema.BoolAttribute{
+				MarkdownDescription: "Whether the configuration test passed.",
+				Computed:            true,
+			},
+			"errors": schema.ListNestedAttribute{
+				MarkdownDescription: "The messages at level `error` or above, which fail the test.",
+				Computed:            true,
+				NestedObject:        messageAttributes,
+			},
+			"warnings": schema.ListNestedAttribute{
+				MarkdownDescription: "The messages below level `error`, such as duplicate MIME types.",
+				Computed:            true,
+				NestedObject:        messageAttributes,
+			},
+			"output": schema.StringAttribute{
+				MarkdownDescription: "The complete output of the test.",
+				Computed:            true,
+			},
+			"effective_config": schema.StringAttribute{
+				MarkdownDescription: "Every configuration file NGINX reads, as printed by `nginx -T`. Null unless " +
+					"`dump` is set.",
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The command that was run.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ConfigTestDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ssh.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ConfigTestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ConfigTestDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	test := "nginx -t"
+	if data.Dump.ValueBool() {
+		test = "nginx -T"
+	}
+
+	// The dump goes to stdout and the messages to stderr, which is collected
+	// separately so that a failing test still reports its exit status
+	command := fmt.Sprintf("log=$(mktemp); sudo %s 2>\"$log\"; status=$?; echo '%s'; cat \"$log\"; rm -f \"$log\"; echo \"$status\"",
+		test, testOutputMarker)
+	output, err := runCommand(d.client.(*ssh.Client), command)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to run '%s': %s", test, err),
+		)
+		return
+	}
+
+	dump, messages, status, err := splitTestOutput(string(output))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to parse the output of '%s': %s", test, err),
+		)
+		return
+	}
+
+	data.Valid = types.BoolValue(status == 0)
+	data.Errors, data.Warnings = parseTestMessages(messages)
+	data.Output = types.StringValue(messages)
+	data.EffectiveConfig = types.StringNull()
+	if data.Dump.ValueBool() {
+		data.EffectiveConfig = types.StringValue(dump)
+	}
+	data.Id = types.StringValue(test)
+
+	tflog.Trace(ctx, fmt.Sprintf("Tested NGINX configuration: %d errors, %d warnings", len(data.Errors), len(data.Warnings)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// splitTestOutput splits the output of the config test command into the
+// configuration dump, the messages of the test and its exit status.
+func splitTestOutput(output string) (string, string, int, error) {
+	dump, rest, ok := strings.Cut(output, testOutputMarker+"\n")
+	if !ok {
+		return "", "", 0, fmt.Errorf("unexpected output %q", output)
+	}
+
+	rest = strings.TrimRight(rest, "\n")
+	index := strings.LastIndex(rest, "\n")
+	status, err := strconv.Atoi(rest[index+1:])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("unexpected exit status %q", rest[index+1:])
+	}
+
+	messages := ""
+	if index >= 0 {
+		messages = rest[:index+1]
+	}
+	return dump, messages, status, nil
+}
+
+// parseTestMessages sorts the messages logged by nginx -t into errors and
+// warnings. Lines without a log level, such as the final "test is
+// successful", are left out.
+func parseTestMessages(output string) ([]ConfigTestMessageModel, []ConfigTestMessageModel) {
+	errors := []ConfigTestMessageModel{}
+	warnings := []ConfigTestMessageModel{}
+
+	for _, line := range strings.Split(output, "\n") {
+		match := testMessagePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		message := ConfigTestMessageModel{
+			Level:   types.StringValue(match[1]),
+			Message: types.StringValue(match[2]),
+			File:    types.StringNull(),
+			Line:    types.Int64Null(),
+		}
+		if match[3] != "" {
+			number, _ := strconv.ParseInt(match[4], 10, 64)
+			message.File = types.StringValue(match[3])
+			message.Line = types.Int64Value(number)
+		}
+
+		if testErrorLevels[match[1]] {
+			errors = append(errors, message)
+		} else {
+			warnings = append(warnings, message)
+		}
+	}
+
+	return errors, warnings
+}
@@ -0,0 +1,152 @@
+package nginx
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// conformValue converts value, as passed to a dynamic function parameter,
+// into target. Object literals and tuples are accepted for objects, maps,
+// lists and sets, attributes missing from an object are set to null, and
+// attributes unknown to target are rejected. where names the value in
+// errors.
+func conformValue(value tftypes.Value, target tftypes.Type, where string) (tftypes.Value, error) {
+	if value.IsNull() {
+		return tftypes.NewValue(target, nil), nil
+	}
+	if !value.IsFullyKnown() {
+		return tftypes.Value{}, fmt.Errorf("%s must be known", where)
+	}
+
+	switch target := target.(type) {
+	case tftypes.Object:
+		var attributes map[string]tftypes.Value
+		if !isMapLike(value.Type()) || value.As(&attributes) != nil {
+			return tftypes.Value{}, fmt.Errorf("%s must be an object", where)
+		}
+		for _, name := range sortedNames(attributes) {
+			if _, ok := target.AttributeTypes[name]; !ok {
+				return tftypes.Value{}, fmt.Errorf("%s has no attribute %q", where, name)
+			}
+		}
+
+		conformed := make(map[string]tftypes.Value, len(target.AttributeTypes))
+		for name, attributeType := range target.AttributeTypes {
+			attribute, ok := attributes[name]
+			if !ok {
+				conformed[name] = tftypes.NewValue(attributeType, nil)
+				continue
+			}
+			var err error
+			if conformed[name], err = conformValue(attribute, attributeType, where+"."+name); err != nil {
+				return tftypes.Value{}, err
+			}
+		}
+		return tftypes.NewValue(target, conformed), nil
+
+	case tftypes.Map:
+		var elements map[string]tftypes.Value
+		if !isMapLike(value.Type()) || value.As(&elements) != nil {
+			return tftypes.Value{}, fmt.Errorf("%s must be a map", where)
+		}
+
+		conformed := make(map[string]tftypes.Value, len(elements))
+		for key, element := range elements {
+			var err error
+			if conformed[key], err = conformValue(element, target.ElementType, fmt.Sprintf("%s[%q]", where, key)); err != nil {
+				return tftypes.Value{}, err
+			}
+		}
+		return tftypes.NewValue(target, conformed), nil
+
+	case tftypes.List, tftypes.Set:
+		var elements []tftypes.Value
+		if !isListLike(value.Type()) || value.As(&elements) != nil {
+			return tftypes.Value{}, fmt.Errorf("%s must be a list", where)
+		}
+
+		var elementType tftypes.Type
+		switch target := target.(type) {
+		case tftypes.List:
+			elementType = target.ElementType
+		case tftypes.Set:
+			elementType = target.ElementType
+		}
+
+		conformed := make([]tftypes.Value, 0, len(elements))
+		for i, element := range elements {
+			converted, err := conformValue(element, elementType, fmt.Sprintf("%s[%d]", where, i))
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			conformed = append(conformed, converted)
+		}
+		return tftypes.NewValue(target, conformed), nil
+	}
+
+	return conformPrimitive(value, target, where)
+}
+
+// conformPrimitive converts a string, number or bool into target, with the
+// conversions Terraform applies between them.
+func conformPrimitive(value tftypes.Value, target tftypes.Type, where string) (tftypes.Value, error) {
+	switch {
+	case value.Type().Equal(target):
+		return value, nil
+
+	case target.Equal(tftypes.String) && value.Type().Equal(tftypes.Number):
+		var number big.Float
+		if err := value.As(&number); err == nil {
+			return tftypes.NewValue(tftypes.String, number.Text('f', -1)), nil
+		}
+
+	case target.Equal(tftypes.String) && value.Type().Equal(tftypes.Bool):
+		var b bool
+		if err := value.As(&b); err == nil {
+			return tftypes.NewValue(tftypes.String, fmt.Sprint(b)), nil
+		}
+
+	case target.Equal(tftypes.Number) && value.Type().Equal(tftypes.String):
+		var s string
+		if err := value.As(&s); err == nil {
+			if number, ok := new(big.Float).SetString(s); ok {
+				return tftypes.NewValue(tftypes.Number, number), nil
+			}
+		}
+	}
+
+	return tftypes.Value{}, fmt.Errorf("%s must be a %s", where, typeName(target))
+}
+
+func isMapLike(t tftypes.Type) bool {
+	return t.Is(tftypes.Object{}) || t.Is(tftypes.Map{})
+}
+
+func isListLike(t tftypes.Type) bool {
+	return t.Is(tftypes.Tuple{}) || t.Is(tftypes.List{}) || t.Is(tftypes.Set{})
+}
+
+// typeName returns the Terraform name of a primitive type.
+func typeName(t tftypes.Type) string {
+	switch {
+	case t.Equal(tftypes.String):
+		return "string"
+	case t.Equal(tftypes.Number):
+		return "number"
+	case t.Equal(tftypes.Bool):
+		return "bool"
+	}
+	return t.String()
+}
+
+func sortedNames(values map[string]tftypes.Value) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
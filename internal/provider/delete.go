@@ -0,0 +1,37 @@
+package nginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"golang.org/x/crypto/ssh"
+)
+
+func keepOnDestroyAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: "Whether to leave the configuration file on the host when the resource is destroyed. " +
+			"Terraform then only stops managing the file. Defaults to `false`.",
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(false),
+	}
+}
+
+// deleteConfigFile removes the configuration file at path from the host,
+// checks that the remaining configuration is still valid and reloads NGINX.
+// The file is put back when the check fails. The boolean result is false
+// when the file was already gone, in which case NGINX is left alone.
+func deleteConfigFile(client *ssh.Client, path string) (bool, error) {
+	quoted := shellQuote(path)
+	script := fmt.Sprintf("if [ ! -f %s ]; then echo '%s'; exit 0; fi; ", quoted, notFoundMarker) +
+		checkedChangeScript(quoted, "sudo rm -f "+quoted)
+
+	output, err := runCheckedChange(client, script)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(string(output)) != notFoundMarker, nil
+}
@@ -0,0 +1,268 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"golang.org/x/crypto/ssh"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// maxDiffCells bounds the size of the table used to compute a diff. Larger
+// inputs are shown as a complete replacement.
+const maxDiffCells = 4 << 20
+
+// diffOp is a single line of an edit script.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// unifiedDiff returns a unified diff that turns a into b, labelled with
+// fromName and toName, or an empty string when they are equal.
+func unifiedDiff(fromName string, toName string, a string, b string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		first := max(start-diffContext, 0)
+		last := min(end+diffContext, len(ops))
+		writeHunk(&out, ops, first, last)
+		start = last
+	}
+
+	return out.String()
+}
+
+// planChanges reports whether the plan changes the file a resource manages:
+// whether it creates the resource, moves the file, or changes attribute, the
+// attribute tracking the file content.
+func planChanges(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, attribute string) bool {
+	if req.State.Raw.IsNull() {
+		return true
+	}
+
+	for _, name := range []string{"path", attribute} {
+		var current, planned attr.Value
+		if diags := req.State.GetAttribute(ctx, path.Root(name), &current); diags.HasError() {
+			return true
+		}
+		if diags := resp.Plan.GetAttribute(ctx, path.Root(name), &planned); diags.HasError() {
+			return true
+		}
+		if !planned.Equal(current) {
+			return true
+		}
+	}
+	return false
+}
+
+// planDiff warns with the unified diff between the file at path on the host
+// and the configuration the plan writes there, so that reviewers see the
+// exact change. Nothing is reported when the file already matches. New files
+// are only announced rather than shown in full, and so are all changes when
+// redact is set, as for content rendered from variables that may hold
+// secrets, so that plan output in CI logs does not carry them.
+func planDiff(client *ssh.Client, filePath string, rendered string, redact bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if filePath == "" {
+		return diags
+	}
+
+	remote, found, err := readRemoteFile(client, filePath)
+	if err != nil {
+		diags.AddWarning(
+			"Configuration Diff Unavailable",
+			fmt.Sprintf("Failed to read %s to compare it with the planned configuration: %s", filePath, err),
+		)
+		return diags
+	}
+
+	switch {
+	case !found:
+		diags.AddWarning(
+			"Planned Configuration Change",
+			fmt.Sprintf("Applying this plan creates %s on the host with %d lines.", filePath, len(splitLines(rendered))),
+		)
+	case remote == rendered:
+		// The file already holds the planned configuration
+	case redact:
+		diags.AddWarning(
+			"Planned Configuration Change",
+			fmt.Sprintf("Applying this plan changes %s on the host. The diff is not shown, as the content may hold secrets.", filePath),
+		)
+	default:
+		diags.AddWarning(
+			"Planned Configuration Change",
+			fmt.Sprintf("Applying this plan changes %s on the host:\n\n%s", filePath,
+				unifiedDiff(filePath+" (on host)", filePath+" (planned)", remote, rendered)),
+		)
+	}
+
+	return diags
+}
+
+// writeHunk writes ops[first:last] as a hunk with its line range header.
+func writeHunk(out *strings.Builder, ops []diffOp, first int, last int) {
+	fromLine, toLine := 1, 1
+	for _, op := range ops[:first] {
+		if op.kind != '+' {
+			fromLine++
+		}
+		if op.kind != '-' {
+			toLine++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+	for _, op := range ops[first:last] {
+		if op.kind != '+' {
+			fromCount++
+		}
+		if op.kind != '-' {
+			toCount++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+	for _, op := range ops[first:last] {
+		out.WriteByte(op.kind)
+		out.WriteString(op.line)
+		if !strings.HasSuffix(op.line, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(line int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// splitLines splits s into lines, keeping the line endings.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes an edit script from a to b using the longest common
+// subsequence of their lines.
+func diffLines(a []string, b []string) []diffOp {
+	// Strip the common prefix and suffix, which keeps the table small for
+	// the usual case of a few changed lines
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+func diffMiddle(a []string, b []string) []diffOp {
+	var ops []diffOp
+
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,50 @@
+package nginx
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &EscapeArgFunction{}
+
+func NewEscapeArgFunction() function.Function {
+	return &EscapeArgFunction{}
+}
+
+// EscapeArgFunction defines the function implementation.
+type EscapeArgFunction struct{}
+
+func (f *EscapeArgFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "escape_arg"
+}
+
+func (f *EscapeArgFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Quotes a directive argument",
+		MarkdownDescription: "Returns the string in a form NGINX reads as a single directive argument, wrapped in " +
+			"double quotes when it contains whitespace, semicolons, braces, quotes or `#`. Variables such as " +
+			"`$host` keep working. A `$` is never escaped, as NGINX has no escape for it: where the directive " +
+			"expands variables, it always starts one.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "arg",
+				MarkdownDescription: "The argument to quote.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *EscapeArgFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arg string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &arg))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, quoteArg(arg)))
+}
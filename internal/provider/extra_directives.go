@@ -0,0 +1,108 @@
+package nginx
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// directiveNamePattern matches a valid NGINX directive name.
+var directiveNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ExtraDirectiveModel describes a directive the structured attributes do not
+// cover. When Block is set, even to an empty list, the directive is rendered
+// as a block holding the child directives.
+type ExtraDirectiveModel struct {
+	Name  types.String               `tfsdk:"name"`
+	Args  []types.String             `tfsdk:"args"`
+	Block []ExtraChildDirectiveModel `tfsdk:"block"`
+}
+
+// ExtraChildDirectiveModel describes a directive inside an extra block.
+type ExtraChildDirectiveModel struct {
+	Name types.String   `tfsdk:"name"`
+	Args []types.String `tfsdk:"args"`
+}
+
+func directiveNameAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "The directive name.",
+		Required:            true,
+		Validators: []validator.String{
+			stringvalidator.RegexMatches(directiveNamePattern, "must be a valid NGINX directive name"),
+		},
+	}
+}
+
+func directiveArgsAttribute() schema.ListAttribute {
+	return schema.ListAttribute{
+		MarkdownDescription: "The directive arguments. Arguments containing whitespace, semicolons, braces, " +
+			"quotes or `#` are quoted automatically; variables such as `$host` are kept intact. " +
+			"A `$` always starts a variable where the directive expands them, since NGINX cannot escape it; " +
+			"for a literal dollar sign, use a variable holding one, such as `${dollar}` with " +
+			"`geo $dollar { default \"$\"; }` in the http context.",
+		ElementType: types.StringType,
+		Optional:    true,
+	}
+}
+
+func extraDirectivesAttribute(placement string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		MarkdownDescription: "Additional directives rendered, in order, " + placement + ".",
+		Optional:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"name": directiveNameAttribute(),
+				"args": directiveArgsAttribute(),
+				"block": schema.ListNestedAttribute{
+					MarkdownDescription: "Child directives. Set this, even to an empty list, to render a block.",
+					Optional:            true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"name": directiveNameAttribute(),
+							"args": directiveArgsAttribute(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// extraDirectives converts the extra directives into renderable directives.
+func extraDirectives(extras []ExtraDirectiveModel) []*Directive {
+	directives := make([]*Directive, 0, len(extras))
+	for _, extra := range extras {
+		d := simpleDirective(extra.Name.ValueString(), stringValues(extra.Args)...)
+		if extra.Block != nil {
+			d.IsBlock = true
+			for _, child := range extra.Block {
+				d.Block = append(d.Block, simpleDirective(child.Name.ValueString(), stringValues(child.Args)...))
+			}
+		}
+		directives = append(directives, d)
+	}
+	return directives
+}
+
+// definesDirective reports whether the extra directives set name at their
+// top level.
+func definesDirective(extras []ExtraDirectiveModel, name string) bool {
+	for _, extra := range extras {
+		if extra.Name.ValueString() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValues(values []types.String) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, value.ValueString())
+	}
+	return result
+}
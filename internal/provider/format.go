@@ -0,0 +1,60 @@
+package nginx
+
+// formatConfig rewrites NGINX configuration in canonical form: one directive
+// per line, tab indentation, single spaces between arguments, arguments
+// quoted only where needed and a single trailing newline. Comments are kept.
+func formatConfig(src string) (string, error) {
+	config, err := parseConfig(src)
+	if err != nil {
+		return "", err
+	}
+	return renderConfig(config), nil
+}
+
+// equivalentConfig reports whether two configurations only differ in
+// formatting and comments. Configurations that cannot be parsed are only
+// equivalent when they are identical.
+func equivalentConfig(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	configA, err := parseConfig(a)
+	if err != nil {
+		return false
+	}
+	configB, err := parseConfig(b)
+	if err != nil {
+		return false
+	}
+
+	return renderConfig(stripComments(configA)) == renderConfig(stripComments(configB))
+}
+
+// refreshContent returns the content attribute to store after reading remote
+// from the host. The previous value is kept when the host holds an
+// equivalent configuration, so formatting alone never shows up as a diff;
+// otherwise the remote configuration is stored in canonical form.
+func refreshContent(previous string, remote string) string {
+	if equivalentConfig(previous, remote) {
+		return previous
+	}
+	if formatted, err := formatConfig(remote); err == nil {
+		return formatted
+	}
+	return remote
+}
+
+// stripComments returns a copy of the directive tree without comments.
+func stripComments(directives []*Directive) []*Directive {
+	stripped := make([]*Directive, 0, len(directives))
+	for _, d := range directives {
+		if d.IsComment {
+			continue
+		}
+		copied := *d
+		copied.Block = stripComments(d.Block)
+		stripped = append(stripped, &copied)
+	}
+	return stripped
+}
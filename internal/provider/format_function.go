@@ -0,0 +1,54 @@
+package nginx
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &FormatFunction{}
+
+func NewFormatFunction() function.Function {
+	return &FormatFunction{}
+}
+
+// FormatFunction defines the function implementation.
+type FormatFunction struct{}
+
+func (f *FormatFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format"
+}
+
+func (f *FormatFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Formats NGINX configuration",
+		MarkdownDescription: "Formats NGINX configuration the way the provider renders it: one directive per line, " +
+			"nested blocks indented with tabs and arguments quoted only where needed. Comments are kept.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "config",
+				MarkdownDescription: "The NGINX configuration to format.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *FormatFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var config string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &config))
+	if resp.Error != nil {
+		return
+	}
+
+	formatted, err := formatConfig(config)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, formatted))
+}
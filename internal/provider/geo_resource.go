@@ -0,0 +1,460 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &GeoResource{}
+var _ resource.ResourceWithImportState = &GeoResource{}
+var _ resource.ResourceWithValidateConfig = &GeoResource{}
+var _ resource.ResourceWithModifyPlan = &GeoResource{}
+var _ resource.ResourceWithConfigValidators = &GeoResource{}
+
+// variableNamePattern matches an NGINX variable reference such as $allowed.
+var variableNamePattern = regexp.MustCompile(`^\$[A-Za-z_][A-Za-z0-9_]*$`)
+
+func NewGeoResource() resource.Resource {
+	return &GeoResource{}
+}
+
+// GeoResource defines the resource implementation.
+type GeoResource struct {
+	client            interface{} // Use interface{} to accept SSH client passed from provider.go
+	configDirectories []string
+}
+
+// GeoResourceModel describes the resource data model.
+type GeoResourceModel struct {
+	GeoName        types.String          `tfsdk:"geo_name"`
+	Variable       types.String          `tfsdk:"variable"`
+	SourceVariable types.String          `tfsdk:"source_variable"`
+	Default        types.String          `tfsdk:"default"`
+	Entries        []GeoEntryModel       `tfsdk:"entries"`
+	Directives     []ExtraDirectiveModel `tfsdk:"directives"`
+	Path           types.String          `tfsdk:"path"`
+	Content        types.String          `tfsdk:"content"`
+	ContentSHA256  types.String          `tfsdk:"content_sha256"`
+	Size           types.Int64           `tfsdk:"size"`
+	Mtime          types.String          `tfsdk:"mtime"`
+	KeepOnDestroy  types.Bool            `tfsdk:"keep_on_destroy"`
+	Id             types.String          `tfsdk:"id"`
+}
+
+// GeoEntryModel maps a list of networks onto a single value.
+type GeoEntryModel struct {
+	Value    types.String   `tfsdk:"value"`
+	Networks []types.String `tfsdk:"networks"`
+}
+
+// directives renders the geo block.
+func (m GeoResourceModel) directives() []*Directive {
+	var args []string
+	if !m.SourceVariable.IsNull() {
+		args = append(args, m.SourceVariable.ValueString())
+	}
+	args = append(args, m.Variable.ValueString())
+
+	geo := blockDirective("geo", args)
+	if !m.Default.IsNull() {
+		geo.Block = append(geo.Block, simpleDirective("default", m.Default.ValueString()))
+	}
+	for _, entry := range m.Entries {
+		for _, network := range entry.Networks {
+			geo.Block = append(geo.Block, simpleDirective(network.ValueString(), entry.Value.ValueString()))
+		}
+	}
+	geo.Block = append(geo.Block, extraDirectives(m.Directives)...)
+
+	return []*Directive{geo}
+}
+
+func (r *GeoResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_geo"
+}
+
+func (r *GeoResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Geo resource. Renders a reusable `geo` map that sets a variable from the client address.",
+
+		Attributes: map[string]schema.Attribute{
+			"geo_name": schema.StringAttribute{
+				MarkdownDescription: "A unique name for the geo resource.",
+				Required:            true,
+			},
+			"variable": schema.StringAttribute{
+				MarkdownDescription: "The variable the geo map sets, for example `$allowed_network`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(variableNamePattern, "must be an NGINX variable such as $allowed_network"),
+				},
+			},
+			"source_variable": schema.StringAttribute{
+				MarkdownDescription: "The variable holding the address to look up. Defaults to `$remote_addr`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(variableNamePattern, "must be an NGINX variable such as $remote_addr"),
+				},
+			},
+			"default": schema.StringAttribute{
+				MarkdownDescription: "The value used when no network matches.",
+				Optional:            true,
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "Networks and the value the variable takes for them. " +
+					"At least one of `entries` and `default` must be set.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value assigned to the variable.",
+							Required:            true,
+						},
+						"networks": schema.ListAttribute{
+							MarkdownDescription: "IP addresses or CIDR networks that map to the value.",
+							ElementType:         types.StringType,
+							Required:            true,
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+								listvalidator.ValueStringsAre(cidrValidator{}),
+							},
+						},
+					},
+				},
+			},
+			"directives": extraDirectivesAttribute("at the end of the geo block"),
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The path of the geo configuration file.",
+				Required:            true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "The rendered geo configuration.",
+				Computed:            true,
+			},
+			"content_sha256":  contentSHA256Attribute(),
+			"size":            sizeAttribute(),
+			"mtime":           mtimeAttribute(),
+			"keep_on_destroy": keepOnDestroyAttribute(),
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ID of the geo resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GeoResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data GeoResourceModel
+
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// NGINX rejects a geo block that lists the same network twice
+	seen := make(map[string]string)
+	for i, entry := range data.Entries {
+		for _, network := range entry.Networks {
+			value := network.ValueString()
+			if previous, ok := seen[value]; ok {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("entries").AtListIndex(i).AtName("networks"),
+					"Duplicate Network",
+					fmt.Sprintf("The network %q is already mapped to %q.", value, previous),
+				)
+				continue
+			}
+			seen[value] = entry.Value.ValueString()
+		}
+	}
+}
+
+// ConfigValidators requires entries or a default value, without which the
+// geo block maps every address to an empty string.
+func (r *GeoResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("entries"),
+			path.MatchRoot("default"),
+		),
+	}
+}
+
+func (r *GeoResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Use the SSH client passed from the provider
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData) // Type assertion to retrieve the SSH client
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.configDirectories = data.configDirectories
+}
+
+// ModifyPlan renders the planned configuration into content and shows the
+// change it makes to the file on the host.
+func (r *GeoResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Configuration files may only be written below the configured directories
+	resp.Diagnostics.Append(checkConfigPath(ctx, req.Plan, r.configDirectories)...)
+
+	// Nothing to render on destroy or while the configuration is not known
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() || r.client == nil {
+		return
+	}
+
+	var plan GeoResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keep the stored content when it only differs in formatting
+	content := renderConfig(plan.directives())
+	if plan.Content.IsUnknown() || !equivalentConfig(plan.Content.ValueString(), content) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), content)...)
+	}
+
+	planChecksum(ctx, resp, content)
+	if planChanges(ctx, req, resp, "content_sha256") {
+		resp.Diagnostics.Append(planDiff(r.client.(*ssh.Client), plan.Path.ValueString(), content, false)...)
+	}
+}
+
+func (r *GeoResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data GeoResourceModel
+
+	// Retrieve the plan data
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content := renderConfig(data.directives())
+
+	// Write the configuration, keeping the previous file if NGINX rejects it
+	sshClient := r.client.(*ssh.Client)
+	if err := applyConfigFile(sshClient, data.Path.ValueString(), content); err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
+
+	data.Id = types.StringValue(data.GeoName.ValueString())
+	data.Content = types.StringValue(content)
+
+	// Save the data into the Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Created geo resource: %s", data.GeoName.ValueString()))
+}
+
+func (r *GeoResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data GeoResourceModel
+
+	// Get the current state from Terraform
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// State written before keep_on_destroy existed has no value for it
+	if data.KeepOnDestroy.IsNull() {
+		data.KeepOnDestroy = types.BoolValue(false)
+	}
+
+	sshClient := r.client.(*ssh.Client)
+	content, found, err := readRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read geo configuration from %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// Handle missing file case
+	if !found {
+		resp.Diagnostics.AddWarning(
+			"Resource Not Found",
+			fmt.Sprintf("The file at path '%s' does not exist. Terraform will remove it from the state.", data.Path.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Keep the stored content unless the host holds a different configuration
+	data.Content = types.StringValue(refreshContent(data.Content.ValueString(), content))
+
+	// Refresh the checksum, which plans a rewrite once the file was edited
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
+
+	// Save the updated state back to Terraform
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GeoResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan GeoResourceModel
+	var state GeoResourceModel
+
+	// Retrieve the updated plan data
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Retrieve the current state data
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content := renderConfig(plan.directives())
+
+	// Write the configuration, keeping the previous file if NGINX rejects it
+	sshClient := r.client.(*ssh.Client)
+	if err := applyConfigFile(sshClient, plan.Path.ValueString(), content); err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", plan.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, plan.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", plan.Path.ValueString(), err),
+		)
+		return
+	}
+	plan.ContentSHA256, plan.Size, plan.Mtime = fileAttributes(info, found)
+
+	// Remove the previous file when the path moved
+	if state.Path.ValueString() != plan.Path.ValueString() {
+		if _, err := deleteConfigFile(sshClient, state.Path.ValueString()); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Stale Configuration File",
+				fmt.Sprintf("Failed to delete previous file at %s: %s", state.Path.ValueString(), err),
+			)
+		}
+	}
+
+	plan.Id = types.StringValue(plan.GeoName.ValueString())
+	plan.Content = types.StringValue(content)
+
+	// Save the updated state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Updated geo resource: %s", plan.GeoName.ValueString()))
+}
+
+func (r *GeoResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data GeoResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.KeepOnDestroy.ValueBool() {
+		tflog.Trace(ctx, fmt.Sprintf("Kept configuration file of geo resource on the host: %s", data.Path.ValueString()))
+		return
+	}
+
+	// Remove the configuration file, then validate and reload NGINX
+	found, err := deleteConfigFile(r.client.(*ssh.Client), data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to delete file at %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	if !found {
+		tflog.Trace(ctx, fmt.Sprintf("Configuration file already removed from the host: %s", data.Path.ValueString()))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Deleted geo resource: %s", data.GeoName.ValueString()))
+}
+
+func (r *GeoResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by name and path
+	idParts := strings.Split(req.ID, ":")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"Expected import ID in format 'geo_name:path'",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("geo_name"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("keep_on_destroy"), false)...)
+}
@@ -0,0 +1,211 @@
+package nginx
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// HeaderModel describes a single add_header directive. The header name is
+// the key of the enclosing map.
+type HeaderModel struct {
+	Value  types.String `tfsdk:"value"`
+	Always types.Bool   `tfsdk:"always"`
+}
+
+// SecurityHeadersModel describes the security header presets of a server
+// block. Every preset is sent with the always flag so that error pages carry
+// the headers too.
+type SecurityHeadersModel struct {
+	ContentSecurityPolicy types.String `tfsdk:"content_security_policy"`
+	HSTS                  *HSTSModel   `tfsdk:"hsts"`
+	XFrameOptions         types.String `tfsdk:"x_frame_options"`
+	ReferrerPolicy        types.String `tfsdk:"referrer_policy"`
+	PermissionsPolicy     types.String `tfsdk:"permissions_policy"`
+}
+
+// HSTSModel describes the Strict-Transport-Security preset.
+type HSTSModel struct {
+	MaxAge            types.Int64 `tfsdk:"max_age"`
+	IncludeSubdomains types.Bool  `tfsdk:"include_subdomains"`
+	Preload           types.Bool  `tfsdk:"preload"`
+}
+
+func headersAttribute(level string) schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		MarkdownDescription: "Response headers added at the " + level + " level, keyed by header name.",
+		Optional:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"value": schema.StringAttribute{
+					MarkdownDescription: "The header value.",
+					Required:            true,
+				},
+				"always": schema.BoolAttribute{
+					MarkdownDescription: "Send the header regardless of the response code.",
+					Optional:            true,
+				},
+			},
+		},
+	}
+}
+
+func securityHeadersAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Presets for common security headers. They are inherited by every location, " +
+			"including locations that define their own `headers`.",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"content_security_policy": schema.StringAttribute{
+				MarkdownDescription: "The `Content-Security-Policy` header value.",
+				Optional:            true,
+			},
+			"hsts": schema.SingleNestedAttribute{
+				MarkdownDescription: "Send a `Strict-Transport-Security` header.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"max_age": schema.Int64Attribute{
+						MarkdownDescription: "How long, in seconds, browsers should only use HTTPS.",
+						Required:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"include_subdomains": schema.BoolAttribute{
+						MarkdownDescription: "Apply the policy to all subdomains.",
+						Optional:            true,
+					},
+					"preload": schema.BoolAttribute{
+						MarkdownDescription: "Allow the domain to be added to browser preload lists.",
+						Optional:            true,
+					},
+				},
+			},
+			"x_frame_options": schema.StringAttribute{
+				MarkdownDescription: "The `X-Frame-Options` header value: `DENY` or `SAMEORIGIN`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("DENY", "SAMEORIGIN"),
+				},
+			},
+			"referrer_policy": schema.StringAttribute{
+				MarkdownDescription: "The `Referrer-Policy` header value.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						"no-referrer",
+						"no-referrer-when-downgrade",
+						"origin",
+						"origin-when-cross-origin",
+						"same-origin",
+						"strict-origin",
+						"strict-origin-when-cross-origin",
+						"unsafe-url",
+					),
+				},
+			},
+			"permissions_policy": schema.StringAttribute{
+				MarkdownDescription: "The `Permissions-Policy` header value.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// header is a resolved add_header directive.
+type header struct {
+	name   string
+	value  string
+	always bool
+}
+
+func (h header) directive() *Directive {
+	d := simpleDirective("add_header", h.name, h.value)
+	if h.always {
+		d.Args = append(d.Args, "always")
+	}
+	return d
+}
+
+// presetHeaders resolves the security header presets, in a fixed order.
+func presetHeaders(presets *SecurityHeadersModel) []header {
+	if presets == nil {
+		return nil
+	}
+
+	var headers []header
+	if !presets.ContentSecurityPolicy.IsNull() {
+		headers = append(headers, header{"Content-Security-Policy", presets.ContentSecurityPolicy.ValueString(), true})
+	}
+	if presets.HSTS != nil {
+		value := "max-age=" + strconv.FormatInt(presets.HSTS.MaxAge.ValueInt64(), 10)
+		if presets.HSTS.IncludeSubdomains.ValueBool() {
+			value += "; includeSubDomains"
+		}
+		if presets.HSTS.Preload.ValueBool() {
+			value += "; preload"
+		}
+		headers = append(headers, header{"Strict-Transport-Security", value, true})
+	}
+	if !presets.XFrameOptions.IsNull() {
+		headers = append(headers, header{"X-Frame-Options", presets.XFrameOptions.ValueString(), true})
+	}
+	if !presets.ReferrerPolicy.IsNull() {
+		headers = append(headers, header{"Referrer-Policy", presets.ReferrerPolicy.ValueString(), true})
+	}
+	if !presets.PermissionsPolicy.IsNull() {
+		headers = append(headers, header{"Permissions-Policy", presets.PermissionsPolicy.ValueString(), true})
+	}
+
+	return headers
+}
+
+// mapHeaders resolves a headers map, sorted by name so the rendered output
+// is stable.
+func mapHeaders(headers map[string]HeaderModel) []header {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resolved := make([]header, 0, len(names))
+	for _, name := range names {
+		resolved = append(resolved, header{name, headers[name].Value.ValueString(), headers[name].Always.ValueBool()})
+	}
+
+	return resolved
+}
+
+// mergeHeaders returns the inherited headers followed by the own headers,
+// dropping inherited headers that own overrides. Header names are compared
+// case-insensitively, as HTTP does.
+func mergeHeaders(inherited []header, own []header) []header {
+	overridden := make(map[string]bool, len(own))
+	for _, h := range own {
+		overridden[strings.ToLower(h.name)] = true
+	}
+
+	merged := make([]header, 0, len(inherited)+len(own))
+	for _, h := range inherited {
+		if !overridden[strings.ToLower(h.name)] {
+			merged = append(merged, h)
+		}
+	}
+
+	return append(merged, own...)
+}
+
+func headerDirectives(headers []header) []*Directive {
+	directives := make([]*Directive, 0, len(headers))
+	for _, h := range headers {
+		directives = append(directives, h.directive())
+	}
+	return directives
+}
@@ -0,0 +1,188 @@
+package nginx
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &HtpasswdHashFunction{}
+
+// apr1Alphabet is the alphabet crypt(3) encodes hashes and salts with.
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func NewHtpasswdHashFunction() function.Function {
+	return &HtpasswdHashFunction{}
+}
+
+// HtpasswdHashFunction defines the function implementation.
+type HtpasswdHashFunction struct{}
+
+func (f *HtpasswdHashFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "htpasswd_hash"
+}
+
+func (f *HtpasswdHashFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds an htpasswd line",
+		MarkdownDescription: "Returns a `user:hash` line for an `auth_basic_user_file`. Functions have to return the " +
+			"same result on every call, so the `apr1` salt is the one passed as last argument, or is derived from the " +
+			"user name alone rather than chosen at random. Bcrypt, which always uses a random salt, is not offered.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "user",
+				MarkdownDescription: "The user name, which must not contain `:` or line breaks.",
+			},
+			function.StringParameter{
+				Name:                "password",
+				MarkdownDescription: "The password.",
+			},
+			function.StringParameter{
+				Name:                "algo",
+				MarkdownDescription: "The hash algorithm: `apr1` for the Apache MD5 scheme, or `sha1` for unsalted SHA-1.",
+				Validators: []function.StringParameterValidator{
+					stringvalidator.OneOf("apr1", "sha1"),
+				},
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name: "salt",
+			MarkdownDescription: "The `apr1` salt: up to eight characters out of `./0-9A-Za-z`, such as the result of " +
+				"a `random_string` resource with `special = false`. Optional; only one may be given.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *HtpasswdHashFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var user, password, algo string
+	var salts []string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &user, &password, &algo, &salts))
+	if resp.Error != nil {
+		return
+	}
+
+	if user == "" || strings.ContainsAny(user, ":\r\n") {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, "The user name must not be empty or contain ':' or line breaks."))
+		return
+	}
+
+	if len(salts) > 1 {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(4, "Only one salt may be given."))
+		return
+	}
+	salt := apr1Salt(user)
+	if len(salts) == 1 {
+		if algo != "apr1" {
+			resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(3, "A salt is only used by apr1."))
+			return
+		}
+		if salts[0] == "" || len(salts[0]) > 8 || strings.Trim(salts[0], apr1Alphabet) != "" {
+			resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(3, "The salt must be one to eight characters out of ./0-9A-Za-z."))
+			return
+		}
+		salt = salts[0]
+	}
+
+	var hash string
+	switch algo {
+	case "apr1":
+		hash = apr1Hash(password, salt)
+	case "sha1":
+		sum := sha1.Sum([]byte(password))
+		hash = "{SHA}" + base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, fmt.Sprintf("%s:%s", user, hash)))
+}
+
+// apr1Salt derives an eight character salt from user. The password is left
+// out, since the salt is stored in the clear and must not reveal anything
+// about it.
+func apr1Salt(user string) string {
+	sum := sha256.Sum256([]byte(user))
+
+	salt := make([]byte, 8)
+	for i := range salt {
+		salt[i] = apr1Alphabet[sum[i]&0x3f]
+	}
+	return string(salt)
+}
+
+// apr1Hash hashes password with the Apache variant of the MD5-based
+// crypt(3) scheme, which NGINX reads as $apr1$salt$hash.
+func apr1Hash(password string, salt string) string {
+	const magic = "$apr1$"
+	pw, s := []byte(password), []byte(salt)
+
+	alternate := md5.New()
+	alternate.Write(pw)
+	alternate.Write(s)
+	alternate.Write(pw)
+	sum := alternate.Sum(nil)
+
+	digest := md5.New()
+	digest.Write(pw)
+	digest.Write([]byte(magic))
+	digest.Write(s)
+	for i := len(pw); i > 0; i -= 16 {
+		digest.Write(sum[:min(i, 16)])
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 == 1 {
+			digest.Write([]byte{0})
+		} else {
+			digest.Write(pw[:1])
+		}
+	}
+	sum = digest.Sum(nil)
+
+	// Stretch the digest to slow down brute force attacks
+	for round := 0; round < 1000; round++ {
+		stretch := md5.New()
+		if round&1 == 1 {
+			stretch.Write(pw)
+		} else {
+			stretch.Write(sum)
+		}
+		if round%3 != 0 {
+			stretch.Write(s)
+		}
+		if round%7 != 0 {
+			stretch.Write(pw)
+		}
+		if round&1 == 1 {
+			stretch.Write(sum)
+		} else {
+			stretch.Write(pw)
+		}
+		sum = stretch.Sum(nil)
+	}
+
+	var encoded strings.Builder
+	encode := func(a byte, b byte, c byte, n int) {
+		v := uint(a)<<16 | uint(b)<<8 | uint(c)
+		for ; n > 0; n-- {
+			encoded.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	encode(sum[0], sum[6], sum[12], 4)
+	encode(sum[1], sum[7], sum[13], 4)
+	encode(sum[2], sum[8], sum[14], 4)
+	encode(sum[3], sum[9], sum[15], 4)
+	encode(sum[4], sum[10], sum[5], 4)
+	encode(0, 0, sum[11], 2)
+
+	return magic + salt + "$" + encoded.String()
+}
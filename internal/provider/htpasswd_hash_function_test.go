@@ -0,0 +1,87 @@
+package nginx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestApr1Hash checks the hashes against openssl passwd -apr1.
+func TestApr1Hash(t *testing.T) {
+	tests := []struct {
+		password string
+		salt     string
+		want     string
+	}{
+		{"p@ss word", "abcdefgh", "$apr1$abcdefgh$aJUuGLjz3OI4ylHF//t5U1"},
+		{"secret", "ab", "$apr1$ab$jiiV6N7hIIuIoJbc1hxOE/"},
+	}
+
+	for _, tt := range tests {
+		if got := apr1Hash(tt.password, tt.salt); got != tt.want {
+			t.Errorf("apr1Hash(%q, %q) = %s, want %s", tt.password, tt.salt, got, tt.want)
+		}
+	}
+}
+
+func runHtpasswdHash(t *testing.T, args ...string) (string, *function.FuncError) {
+	t.Helper()
+
+	values := []attr.Value{types.StringValue(args[0]), types.StringValue(args[1]), types.StringValue(args[2])}
+	var salts []attr.Value
+	for _, salt := range args[3:] {
+		salts = append(salts, types.StringValue(salt))
+	}
+	elementTypes := make([]attr.Type, len(salts))
+	for i := range elementTypes {
+		elementTypes[i] = types.StringType
+	}
+	values = append(values, types.TupleValueMust(elementTypes, salts))
+
+	req := function.RunRequest{Arguments: function.NewArgumentsData(values)}
+	resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	(&HtpasswdHashFunction{}).Run(context.Background(), req, &resp)
+
+	result, _ := resp.Result.Value().(types.String)
+	return result.ValueString(), resp.Error
+}
+
+// TestHtpasswdHashSalt checks that the derived salt depends on the user name
+// alone and that an explicit salt is used as given.
+func TestHtpasswdHashSalt(t *testing.T) {
+	first, err := runHtpasswdHash(t, "alice", "one", "apr1")
+	if err != nil {
+		t.Fatalf("htpasswd_hash() error = %s", err)
+	}
+	second, err := runHtpasswdHash(t, "alice", "two", "apr1")
+	if err != nil {
+		t.Fatalf("htpasswd_hash() error = %s", err)
+	}
+	salt := "$apr1$" + apr1Salt("alice") + "$"
+	if !strings.HasPrefix(first, "alice:"+salt) || !strings.HasPrefix(second, "alice:"+salt) {
+		t.Errorf("htpasswd_hash() = %s and %s, want both salted with %s", first, second, salt)
+	}
+
+	got, err := runHtpasswdHash(t, "alice", "secret", "apr1", "ab")
+	if err != nil {
+		t.Fatalf("htpasswd_hash() error = %s", err)
+	}
+	if want := "alice:$apr1$ab$jiiV6N7hIIuIoJbc1hxOE/"; got != want {
+		t.Errorf("htpasswd_hash() = %s, want %s", got, want)
+	}
+
+	for _, args := range [][]string{
+		{"alice", "secret", "apr1", "not-valid"},
+		{"alice", "secret", "apr1", ""},
+		{"alice", "secret", "sha1", "ab"},
+		{"alice", "secret", "apr1", "ab", "cd"},
+	} {
+		if _, err := runHtpasswdHash(t, args...); err == nil {
+			t.Errorf("htpasswd_hash(%q) succeeded, want an error", args)
+		}
+	}
+}
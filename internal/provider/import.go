@@ -0,0 +1,307 @@
+package nginx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// importedServer holds the attributes reconstructed from a server block on
+// the host during import.
+type importedServer struct {
+	Settings   serverSettings
+	Access     *AccessModel
+	Headers    map[string]HeaderModel
+	Locations  []LocationModel
+	Directives []ExtraDirectiveModel
+	Content    string
+}
+
+// serverBlock maps the imported attributes onto the server block renderer.
+func (s importedServer) serverBlock() serverBlock {
+	return serverBlock{
+		ListenPort: s.Settings.ListenPort,
+		ServerName: s.Settings.ServerName,
+		Root:       s.Settings.Root,
+		Access:     s.Access,
+		Headers:    s.Headers,
+		Locations:  s.Locations,
+		Directives: s.Directives,
+	}
+}
+
+// importServer reads the file at path and reconstructs the typed attributes
+// of its server block. With structuredLocations set, location blocks become
+// locations; otherwise they are kept as extra directives. Anything that
+// cannot be represented is reported as a warning, because the next apply
+// would drop it.
+func importServer(client *ssh.Client, path string, structuredLocations bool) (importedServer, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var imported importedServer
+
+	content, found, err := readRemoteFile(client, path)
+	if err != nil {
+		diags.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read %s: %s", path, err),
+		)
+		return imported, diags
+	}
+	if !found {
+		diags.AddError(
+			"Resource Not Found",
+			fmt.Sprintf("The file at path '%s' does not exist.", path),
+		)
+		return imported, diags
+	}
+
+	config, err := parseConfig(content)
+	if err != nil {
+		diags.AddError(
+			"Unparsable Configuration",
+			fmt.Sprintf("Failed to parse %s: %s", path, err),
+		)
+		return imported, diags
+	}
+
+	servers := findDirectives(config, "server")
+	if len(servers) == 0 {
+		diags.AddError(
+			"Missing Server Block",
+			fmt.Sprintf("The file at path '%s' does not contain a server block.", path),
+		)
+		return imported, diags
+	}
+	if len(servers) > 1 {
+		diags.AddWarning(
+			"Multiple Server Blocks",
+			fmt.Sprintf("The file at path '%s' contains %d server blocks; only the first one was imported.", path, len(servers)),
+		)
+	}
+
+	imported = importServerBlock(servers[0], structuredLocations)
+	imported.Content = content
+
+	// Render what Terraform would write back and report what gets lost
+	rendered := imported.serverBlock().directives()
+	if drift := configDrift(config, rendered); len(drift) > 0 {
+		diags.AddWarning(
+			"Unrepresentable Configuration",
+			fmt.Sprintf("Some of the configuration in '%s' cannot be represented by the resource attributes "+
+				"and would change on the next apply:\n\n- %s", path, strings.Join(drift, "\n- ")),
+		)
+	}
+
+	return imported, diags
+}
+
+func importServerBlock(server *Directive, structuredLocations bool) importedServer {
+	settings, _ := parseServerSettings([]*Directive{server})
+	imported := importedServer{Settings: settings}
+
+	var locations []*Directive
+	seen := make(map[string]bool)
+	for _, d := range server.Block {
+		if d.IsComment {
+			continue
+		}
+
+		// The first listen, server_name and root map onto typed attributes;
+		// index is always rendered by the template
+		if !seen[d.Name] && (d.Name == "listen" || d.Name == "server_name" || d.Name == "root") || d.Name == "index" {
+			seen[d.Name] = true
+			continue
+		}
+
+		switch d.Name {
+		case "allow", "deny", "satisfy":
+			imported.Access = addAccessDirective(imported.Access, d)
+		case "add_header":
+			if h, ok := importHeader(d, imported.Headers); ok {
+				imported.Headers = addHeader(imported.Headers, h)
+				continue
+			}
+			imported.Directives = appendExtraDirective(imported.Directives, d)
+		case "location":
+			locations = append(locations, d)
+		default:
+			imported.Directives = appendExtraDirective(imported.Directives, d)
+		}
+	}
+
+	// The template renders the static-file location itself when there are
+	// no other locations
+	if len(locations) == 1 && isDefaultLocation(locations[0]) {
+		return imported
+	}
+
+	for _, location := range locations {
+		switch {
+		case structuredLocations:
+			imported.Locations = append(imported.Locations, importLocation(location))
+		case isDefaultLocation(location):
+			continue
+		default:
+			imported.Directives = appendExtraDirective(imported.Directives, location)
+		}
+	}
+
+	return imported
+}
+
+func importLocation(d *Directive) LocationModel {
+	location := LocationModel{
+		Path:     types.StringNull(),
+		Modifier: types.StringNull(),
+	}
+	switch len(d.Args) {
+	case 1:
+		location.Path = types.StringValue(d.Args[0])
+	case 2:
+		location.Modifier = types.StringValue(d.Args[0])
+		location.Path = types.StringValue(d.Args[1])
+	}
+
+	for _, child := range d.Block {
+		if child.IsComment {
+			continue
+		}
+
+		switch child.Name {
+		case "try_files":
+			if location.TryFiles == nil {
+				location.TryFiles = stringList(child.Args)
+				continue
+			}
+			location.Directives = appendExtraDirective(location.Directives, child)
+		case "allow", "deny", "satisfy":
+			location.Access = addAccessDirective(location.Access, child)
+		case "add_header":
+			if h, ok := importHeader(child, location.Headers); ok {
+				location.Headers = addHeader(location.Headers, h)
+				continue
+			}
+			location.Directives = appendExtraDirective(location.Directives, child)
+		default:
+			location.Directives = appendExtraDirective(location.Directives, child)
+		}
+	}
+
+	return location
+}
+
+// isDefaultLocation reports whether d is the static-file location the
+// template renders when no locations are configured.
+func isDefaultLocation(d *Directive) bool {
+	if len(d.Args) != 1 || d.Args[0] != "/" || d.Raw != "" {
+		return false
+	}
+
+	var children []*Directive
+	for _, child := range d.Block {
+		if !child.IsComment {
+			children = append(children, child)
+		}
+	}
+
+	return len(children) == 1 && children[0].Name == "try_files" &&
+		strings.Join(children[0].Args, " ") == "$uri $uri/ =404"
+}
+
+func addAccessDirective(access *AccessModel, d *Directive) *AccessModel {
+	if access == nil {
+		access = &AccessModel{Satisfy: types.StringNull(), Rules: []AccessRuleModel{}}
+	}
+	if len(d.Args) != 1 {
+		return access
+	}
+
+	if d.Name == "satisfy" {
+		access.Satisfy = types.StringValue(d.Args[0])
+		return access
+	}
+	access.Rules = append(access.Rules, AccessRuleModel{
+		Action: types.StringValue(d.Name),
+		Source: types.StringValue(d.Args[0]),
+	})
+	return access
+}
+
+// importHeader converts an add_header directive into a header. It fails
+// for headers that are already set, because the headers map holds one value
+// per name.
+func importHeader(d *Directive, headers map[string]HeaderModel) (header, bool) {
+	if len(d.Args) > 0 {
+		if _, exists := headers[d.Args[0]]; exists {
+			return header{}, false
+		}
+	}
+
+	switch {
+	case len(d.Args) == 2:
+		return header{d.Args[0], d.Args[1], false}, true
+	case len(d.Args) == 3 && d.Args[2] == "always":
+		return header{d.Args[0], d.Args[1], true}, true
+	}
+	return header{}, false
+}
+
+func addHeader(headers map[string]HeaderModel, h header) map[string]HeaderModel {
+	if headers == nil {
+		headers = make(map[string]HeaderModel)
+	}
+
+	always := types.BoolNull()
+	if h.always {
+		always = types.BoolValue(true)
+	}
+	headers[h.name] = HeaderModel{Value: types.StringValue(h.value), Always: always}
+
+	return headers
+}
+
+// appendExtraDirective keeps d as an extra directive. Directives that extra
+// directives cannot express, such as nested blocks two levels deep or Lua
+// blocks, are left out and show up in the import warning instead.
+func appendExtraDirective(extras []ExtraDirectiveModel, d *Directive) []ExtraDirectiveModel {
+	extra := ExtraDirectiveModel{
+		Name: types.StringValue(d.Name),
+		Args: stringList(d.Args),
+	}
+
+	if d.IsBlock {
+		if d.Raw != "" {
+			return extras
+		}
+		extra.Block = []ExtraChildDirectiveModel{}
+		for _, child := range d.Block {
+			if child.IsComment {
+				continue
+			}
+			if child.IsBlock {
+				return extras
+			}
+			extra.Block = append(extra.Block, ExtraChildDirectiveModel{
+				Name: types.StringValue(child.Name),
+				Args: stringList(child.Args),
+			})
+		}
+	}
+
+	return append(extras, extra)
+}
+
+func stringList(values []string) []types.String {
+	if len(values) == 0 {
+		return nil
+	}
+
+	list := make([]types.String, 0, len(values))
+	for _, value := range values {
+		list = append(list, types.StringValue(value))
+	}
+	return list
+}
@@ -0,0 +1,337 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &InfoDataSource{}
+
+// processListMarker separates the output of nginx -V from the process list
+// in the command run by the info data source.
+const processListMarker = "--- processes ---"
+
+// defaultPrefix is the installation prefix NGINX is built with when
+// configure is not given --prefix.
+const defaultPrefix = "/usr/local/nginx"
+
+func NewInfoDataSource() datasource.DataSource {
+	return &InfoDataSource{}
+}
+
+// InfoDataSource defines the data source implementation.
+type InfoDataSource struct {
+	client interface{} // Use interface{} to accept SSH client passed from provider.go
+}
+
+// InfoDataSourceModel describes the data source data model.
+type InfoDataSourceModel struct {
+	Version            types.String   `tfsdk:"version"`
+	ConfigureArguments []types.String `tfsdk:"configure_arguments"`
+	Modules            []types.String `tfsdk:"modules"`
+	DynamicModules     []types.String `tfsdk:"dynamic_modules"`
+	Prefix             types.String   `tfsdk:"prefix"`
+	ConfPath           types.String   `tfsdk:"conf_path"`
+	ErrorLogPath       types.String   `tfsdk:"error_log_path"`
+	PidPath            types.String   `tfsdk:"pid_path"`
+	User               types.String   `tfsdk:"user"`
+	Id                 types.String   `tfsdk:"id"`
+}
+
+// nginxBuild holds what nginx -V reports about the binary.
+type nginxBuild struct {
+	version        string
+	arguments      []string
+	modules        []string
+	dynamicModules []string
+	prefix         string
+	confPath       string
+	errorLogPath   string
+	pidPath        string
+	user           string
+}
+
+func (d *InfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_info"
+}
+
+func (d *InfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Describes the NGINX binary on the host, as reported by `nginx -V`, so that " +
+			"configurations can depend on the version and the available modules.",
+
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				MarkdownDescription: "The NGINX version, such as `1.24.0`.",
+				Computed:            true,
+			},
+			"configure_arguments": schema.ListAttribute{
+				MarkdownDescription: "The arguments NGINX was configured with when it was built.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"modules": schema.ListAttribute{
+				MarkdownDescription: "The optional modules compiled into the binary, such as `http_ssl_module`. " +
+					"Third-party modules are named after their source directory.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"dynamic_modules": schema.ListAttribute{
+				MarkdownDescription: "The modules built as dynamic modules, which are only available once loaded " +
+					"with `load_module`.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "The installation prefix relative paths are resolved against.",
+				Computed:            true,
+			},
+			"conf_path": schema.StringAttribute{
+				MarkdownDescription: "The main configuration file.",
+				Computed:            true,
+			},
+			"error_log_path": schema.StringAttribute{
+				MarkdownDescription: "The default error log.",
+				Computed:            true,
+			},
+			"pid_path": schema.StringAttribute{
+				MarkdownDescription: "The file holding the process ID of the master process.",
+				Computed:            true,
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The user the worker processes run as. It is taken from the running workers " +
+					"when NGINX is running, otherwise from the build default.",
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The NGINX version.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *InfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ssh.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *InfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data InfoDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// nginx -V prints to stderr; the process list tells which user the
+	// workers run as
+	command := fmt.Sprintf("sudo nginx -V 2>&1; echo '%s'; ps -eo user=,args= 2>/dev/null || true", processListMarker)
+	output, err := runCommand(d.client.(*ssh.Client), command)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to run 'nginx -V': %s", err),
+		)
+		return
+	}
+
+	version, processes, _ := strings.Cut(string(output), processListMarker)
+	build, err := parseNginxBuild(version)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to parse the output of 'nginx -V': %s", err),
+		)
+		return
+	}
+	if user := workerUser(processes); user != "" {
+		build.user = user
+	}
+
+	data.Version = types.StringValue(build.version)
+	data.ConfigureArguments = nonNullStringList(build.arguments)
+	data.Modules = nonNullStringList(build.modules)
+	data.DynamicModules = nonNullStringList(build.dynamicModules)
+	data.Prefix = types.StringValue(build.prefix)
+	data.ConfPath = types.StringValue(build.confPath)
+	data.ErrorLogPath = types.StringValue(build.errorLogPath)
+	data.PidPath = types.StringValue(build.pidPath)
+	data.User = types.StringValue(build.user)
+	data.Id = types.StringValue(build.version)
+
+	tflog.Trace(ctx, fmt.Sprintf("Read NGINX %s with %d modules", build.version, len(build.modules)+len(build.dynamicModules)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseNginxBuild parses the output of nginx -V. Paths NGINX was not
+// configured with are filled in with the defaults it uses for them.
+func parseNginxBuild(output string) (nginxBuild, error) {
+	build := nginxBuild{
+		arguments:      []string{},
+		modules:        []string{},
+		dynamicModules: []string{},
+		user:           "nobody",
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "nginx version: "); ok {
+			// Such as "nginx/1.24.0 (Ubuntu)" or "openresty/1.21.4.1"
+			name, _, _ := strings.Cut(rest, " ")
+			_, build.version, _ = strings.Cut(name, "/")
+		}
+		if rest, ok := strings.CutPrefix(line, "configure arguments:"); ok {
+			arguments, err := splitArguments(rest)
+			if err != nil {
+				return nginxBuild{}, err
+			}
+			build.arguments = arguments
+		}
+	}
+	if build.version == "" {
+		return nginxBuild{}, fmt.Errorf("no version in %q", strings.TrimSpace(output))
+	}
+
+	var confPath, errorLogPath, pidPath string
+	for _, argument := range build.arguments {
+		name, value, _ := strings.Cut(argument, "=")
+		switch name {
+		case "--prefix":
+			build.prefix = value
+		case "--conf-path":
+			confPath = value
+		case "--error-log-path":
+			errorLogPath = value
+		case "--pid-path":
+			pidPath = value
+		case "--user":
+			build.user = value
+		case "--add-module":
+			build.modules = append(build.modules, path.Base(value))
+		case "--add-dynamic-module":
+			build.dynamicModules = append(build.dynamicModules, path.Base(value))
+		default:
+			module, ok := strings.CutPrefix(name, "--with-")
+			if !ok || !(strings.HasSuffix(module, "_module") || module == "stream" || module == "mail") {
+				continue
+			}
+			if value == "dynamic" {
+				build.dynamicModules = append(build.dynamicModules, module)
+			} else {
+				build.modules = append(build.modules, module)
+			}
+		}
+	}
+
+	if build.prefix == "" {
+		build.prefix = defaultPrefix
+	}
+	build.confPath = prefixedPath(build.prefix, confPath, "conf/nginx.conf")
+	build.errorLogPath = prefixedPath(build.prefix, errorLogPath, "logs/error.log")
+	build.pidPath = prefixedPath(build.prefix, pidPath, "logs/nginx.pid")
+
+	return build, nil
+}
+
+// prefixedPath resolves a path configured at build time, or fallback when
+// it was not configured, against prefix the way NGINX does.
+func prefixedPath(prefix string, configured string, fallback string) string {
+	if configured == "" {
+		configured = fallback
+	}
+	if path.IsAbs(configured) || configured == "stderr" {
+		return configured
+	}
+	return path.Join(prefix, configured)
+}
+
+// splitArguments splits the configure arguments reported by nginx -V into
+// words, honouring the quotes around values such as --with-cc-opt.
+func splitArguments(input string) ([]string, error) {
+	arguments := []string{}
+
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range input {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				arguments = append(arguments, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in configure arguments")
+	}
+	if inWord {
+		arguments = append(arguments, word.String())
+	}
+
+	return arguments, nil
+}
+
+// workerUser returns the user running the NGINX worker processes in the
+// output of ps -eo user=,args=, or an empty string when none are running.
+func workerUser(processes string) string {
+	for _, line := range strings.Split(processes, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && strings.HasPrefix(fields[1], "nginx:") && fields[2] == "worker" {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// nonNullStringList converts values into a list attribute value. Unlike
+// stringList, no values give an empty list rather than null, so that the
+// result can be passed to contains().
+func nonNullStringList(values []string) []types.String {
+	list := make([]types.String, 0, len(values))
+	for _, value := range values {
+		list = append(list, types.StringValue(value))
+	}
+	return list
+}
@@ -0,0 +1,515 @@
+package nginx
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ListenersDataSource{}
+
+// listenersCommand lists the sockets with ss, or dumps /proc/net and the
+// socket inodes held by the NGINX processes when ss is missing, followed by
+// the configuration dump. Each part starts with a "--- name ---" line. A
+// configuration that cannot be dumped leaves the last part empty rather than
+// failing the command.
+const listenersCommand = `if command -v ss >/dev/null 2>&1; then
+  echo '--- ss ---'; sudo ss -H -ltnup
+else
+  for f in tcp tcp6 udp udp6; do echo "--- $f ---"; cat /proc/net/$f 2>/dev/null; done
+  echo '--- inodes ---'; for p in $(pgrep -x nginx); do sudo ls -l /proc/$p/fd 2>/dev/null; done
+fi
+echo '--- config ---'; sudo nginx -T 2>/dev/null || true`
+
+// defaultListenPort is the port of a server block without listen directive
+// when NGINX runs as root.
+const defaultListenPort = 80
+
+// sectionPattern matches the line starting each part of the output of
+// listenersCommand.
+var sectionPattern = regexp.MustCompile(`^--- (\w+) ---$`)
+
+// socketInodePattern matches a socket in the file descriptors listed by ls.
+var socketInodePattern = regexp.MustCompile(`socket:\[(\d+)\]`)
+
+// procListenStates are the /proc/net states of listening sockets: LISTEN
+// for TCP and unconnected for UDP.
+var procListenStates = map[string]string{
+	"tcp": "0A",
+	"udp": "07",
+}
+
+func NewListenersDataSource() datasource.DataSource {
+	return &ListenersDataSource{}
+}
+
+// ListenersDataSource defines the data source implementation.
+type ListenersDataSource struct {
+	client interface{} // Use interface{} to accept SSH client passed from provider.go
+}
+
+// ListenersDataSourceModel describes the data source data model.
+type ListenersDataSourceModel struct {
+	Source    types.String    `tfsdk:"source"`
+	Listeners []ListenerModel `tfsdk:"listeners"`
+	Id        types.String    `tfsdk:"id"`
+}
+
+// ListenerModel describes a socket NGINX listens on.
+type ListenerModel struct {
+	Address  types.String          `tfsdk:"address"`
+	Port     types.Int64           `tfsdk:"port"`
+	Protocol types.String          `tfsdk:"protocol"`
+	Servers  []ListenerServerModel `tfsdk:"servers"`
+}
+
+// ListenerServerModel describes a server block served by a socket.
+type ListenerServerModel struct {
+	File        types.String   `tfsdk:"file"`
+	Line        types.Int64    `tfsdk:"line"`
+	ServerNames []types.String `tfsdk:"server_names"`
+	Listen      types.String   `tfsdk:"listen"`
+}
+
+// listenSocket is a listening socket, or the socket a listen directive asks
+// for.
+type listenSocket struct {
+	address  string
+	port     int64
+	protocol string
+}
+
+func (d *ListenersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_listeners"
+}
+
+func (d *ListenersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the sockets NGINX listens on, as reported by `ss` or `/proc/net` when `ss` is " +
+			"missing, together with the HTTP server blocks each socket serves.",
+
+		Attributes: map[string]schema.Attribute{
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Where the sockets were read from: `ss` or `proc`.",
+				Computed:            true,
+			},
+			"listeners": schema.ListNestedAttribute{
+				MarkdownDescription: "The listening sockets, sorted by protocol, port and address.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"address": schema.StringAttribute{
+							MarkdownDescription: "The local address, such as `0.0.0.0`, `::` or `127.0.0.1`.",
+							Computed:            true,
+						},
+						"port": schema.Int64Attribute{
+							MarkdownDescription: "The local port.",
+							Computed:            true,
+						},
+						"protocol": schema.StringAttribute{
+							MarkdownDescription: "`tcp`, or `udp` for QUIC.",
+							Computed:            true,
+						},
+						"servers": schema.ListNestedAttribute{
+							MarkdownDescription: "The server blocks whose `listen` directives the socket serves. A " +
+								"wildcard socket also serves the listen directives for specific addresses of the same " +
+								"family on its port that have no socket of their own.",
+							Computed: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"file": schema.StringAttribute{
+										MarkdownDescription: "The file holding the server block.",
+										Computed:            true,
+									},
+									"line": schema.Int64Attribute{
+										MarkdownDescription: "The line the server block starts on.",
+										Computed:            true,
+									},
+									"server_names": schema.ListAttribute{
+										MarkdownDescription: "The names from the `server_name` directives.",
+										ElementType:         types.StringType,
+										Computed:            true,
+									},
+									"listen": schema.StringAttribute{
+										MarkdownDescription: "The arguments of the `listen` directive, or an empty " +
+											"string for a server block without one.",
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The source of the sockets.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ListenersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ssh.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ListenersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ListenersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	output, err := runCommand(d.client.(*ssh.Client), listenersCommand)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to list the listening sockets: %s", err),
+		)
+		return
+	}
+	sections := splitSections(string(output))
+
+	var sockets []listenSocket
+	if ss, ok := sections["ss"]; ok {
+		data.Source = types.StringValue("ss")
+		sockets = parseSSListeners(ss)
+	} else {
+		data.Source = types.StringValue("proc")
+		inodes := make(map[string]bool)
+		for _, match := range socketInodePattern.FindAllStringSubmatch(sections["inodes"], -1) {
+			inodes[match[1]] = true
+		}
+		for _, table := range []string{"tcp", "tcp6", "udp", "udp6"} {
+			sockets = append(sockets, parseProcListeners(strings.TrimSuffix(table, "6"), sections[table], inodes)...)
+		}
+	}
+	sockets = uniqueSockets(sockets)
+
+	// Map the sockets back to the server blocks claiming them
+	var servers []discoveredServer
+	order, files := splitConfigDump(sections["config"])
+	if len(order) == 0 {
+		resp.Diagnostics.AddWarning(
+			"Configuration Unavailable",
+			"The NGINX configuration could not be dumped with 'nginx -T', so the listeners are not mapped to server blocks.",
+		)
+	} else {
+		discovered, diags := discoverServers(order, files)
+		resp.Diagnostics.Append(diags...)
+		servers = discovered
+	}
+
+	data.Listeners = make([]ListenerModel, 0, len(sockets))
+	for _, socket := range sockets {
+		data.Listeners = append(data.Listeners, ListenerModel{
+			Address:  types.StringValue(socket.address),
+			Port:     types.Int64Value(socket.port),
+			Protocol: types.StringValue(socket.protocol),
+			Servers:  claimingServers(socket, sockets, servers),
+		})
+	}
+	data.Id = data.Source
+
+	tflog.Trace(ctx, fmt.Sprintf("Found %d listening sockets", len(sockets)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// splitSections splits the output of listenersCommand into its parts. The
+// configuration dump comes last and is taken as is.
+func splitSections(output string) map[string]string {
+	sections := make(map[string]string)
+
+	var current string
+	var content strings.Builder
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if match := sectionPattern.FindStringSubmatch(strings.TrimRight(line, "\n")); match != nil && current != "config" {
+			if current != "" {
+				sections[current] = content.String()
+			}
+			current = match[1]
+			content.Reset()
+			continue
+		}
+		content.WriteString(line)
+	}
+	if current != "" {
+		sections[current] = content.String()
+	}
+
+	return sections
+}
+
+// parseSSListeners returns the sockets of NGINX processes in the output of
+// ss -H -ltnup, whose lines look like:
+//
+//	tcp LISTEN 0 511 0.0.0.0:80 0.0.0.0:* users:(("nginx",pid=1235,fd=6))
+func parseSSListeners(output string) []listenSocket {
+	var sockets []listenSocket
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 7 || !strings.Contains(strings.Join(fields[6:], " "), `"nginx"`) {
+			continue
+		}
+
+		separator := strings.LastIndex(fields[4], ":")
+		if separator < 0 {
+			continue
+		}
+		port, err := strconv.ParseInt(fields[4][separator+1:], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		// Drop the brackets around IPv6 addresses and any interface suffix
+		address := strings.Trim(fields[4][:separator], "[]")
+		address, _, _ = strings.Cut(address, "%")
+
+		sockets = append(sockets, listenSocket{address: address, port: port, protocol: fields[0]})
+	}
+	return sockets
+}
+
+// parseProcListeners returns the listening sockets in a /proc/net table for
+// protocol that belong to one of inodes, the sockets held by NGINX.
+func parseProcListeners(protocol string, table string, inodes map[string]bool) []listenSocket {
+	var sockets []listenSocket
+	for _, line := range strings.Split(table, "\n") {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when
+		// retrnsmt uid timeout inode
+		fields := strings.Fields(line)
+		if len(fields) < 10 || fields[3] != procListenStates[protocol] || !inodes[fields[9]] {
+			continue
+		}
+
+		hexAddress, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		address, err := decodeProcAddress(hexAddress)
+		if err != nil {
+			continue
+		}
+		port, err := strconv.ParseInt(hexPort, 16, 64)
+		if err != nil {
+			continue
+		}
+
+		sockets = append(sockets, listenSocket{address: address.String(), port: port, protocol: protocol})
+	}
+	return sockets
+}
+
+// decodeProcAddress decodes an address from /proc/net, which is printed as
+// 32-bit words in host byte order, little-endian on the usual hosts.
+func decodeProcAddress(encoded string) (net.IP, error) {
+	raw, err := hex.DecodeString(encoded)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, fmt.Errorf("unexpected address %q", encoded)
+	}
+
+	ip := make(net.IP, len(raw))
+	for word := 0; word < len(raw); word += 4 {
+		for i := 0; i < 4; i++ {
+			ip[word+i] = raw[word+3-i]
+		}
+	}
+	return ip, nil
+}
+
+// uniqueSockets removes the duplicates of sockets shared by several worker
+// processes and sorts them by protocol, port and address.
+func uniqueSockets(sockets []listenSocket) []listenSocket {
+	seen := make(map[listenSocket]bool)
+	unique := make([]listenSocket, 0, len(sockets))
+	for _, socket := range sockets {
+		if !seen[socket] {
+			seen[socket] = true
+			unique = append(unique, socket)
+		}
+	}
+
+	sort.Slice(unique, func(i, j int) bool {
+		if unique[i].protocol != unique[j].protocol {
+			return unique[i].protocol < unique[j].protocol
+		}
+		if unique[i].port != unique[j].port {
+			return unique[i].port < unique[j].port
+		}
+		return unique[i].address < unique[j].address
+	})
+	return unique
+}
+
+// claimingServers returns the server blocks served by socket, given every
+// listening socket.
+func claimingServers(socket listenSocket, sockets []listenSocket, servers []discoveredServer) []ListenerServerModel {
+	claiming := []ListenerServerModel{}
+	for _, server := range servers {
+		for _, listen := range serverListens(server.server) {
+			requested, ok := parseListen(listen)
+			if !ok || !servesListen(socket, requested, sockets) {
+				continue
+			}
+
+			model := server.model()
+			claiming = append(claiming, ListenerServerModel{
+				File:        model.File,
+				Line:        model.Line,
+				ServerNames: model.ServerNames,
+				Listen:      types.StringValue(strings.Join(listen, " ")),
+			})
+		}
+	}
+	return claiming
+}
+
+// serverListens returns the arguments of the listen directives of server. A
+// server block without listen directive listens on the default port.
+func serverListens(server *Directive) [][]string {
+	var listens [][]string
+	for _, d := range server.Block {
+		if !d.IsComment && d.Name == "listen" && len(d.Args) > 0 {
+			listens = append(listens, d.Args)
+		}
+	}
+	if len(listens) == 0 {
+		listens = append(listens, []string{})
+	}
+	return listens
+}
+
+// parseListen returns the socket a listen directive asks for. Unix domain
+// sockets are not reported.
+func parseListen(args []string) (listenSocket, bool) {
+	requested := listenSocket{address: "0.0.0.0", port: defaultListenPort, protocol: "tcp"}
+	for _, arg := range args[min(1, len(args)):] {
+		if arg == "quic" {
+			requested.protocol = "udp"
+		}
+	}
+	if len(args) == 0 {
+		return requested, true
+	}
+
+	address := args[0]
+	if strings.HasPrefix(address, "unix:") {
+		return listenSocket{}, false
+	}
+
+	// The port alone, an address alone, or both
+	var port string
+	switch {
+	case strings.HasPrefix(address, "["):
+		end := strings.Index(address, "]")
+		if end < 0 {
+			return listenSocket{}, false
+		}
+		port = strings.TrimPrefix(address[end+1:], ":")
+		address = address[1:end]
+	case strings.Contains(address, ":"):
+		address, port, _ = strings.Cut(address, ":")
+	case strings.Trim(address, "0123456789") == "":
+		address, port = "", address
+	}
+
+	switch address {
+	case "", "*":
+		address = "0.0.0.0"
+	case "localhost":
+		address = "127.0.0.1"
+	}
+	requested.address = address
+
+	if port != "" {
+		value, err := strconv.ParseInt(port, 10, 64)
+		if err != nil {
+			return listenSocket{}, false
+		}
+		requested.port = value
+	}
+
+	return requested, true
+}
+
+// servesListen reports whether socket serves the listen directive asking for
+// requested. Without the bind parameter, NGINX serves listen directives for
+// specific addresses through the wildcard socket on the same port.
+func servesListen(socket listenSocket, requested listenSocket, sockets []listenSocket) bool {
+	if socket.protocol != requested.protocol || socket.port != requested.port {
+		return false
+	}
+	if sameAddress(socket.address, requested.address) {
+		return true
+	}
+	if !isWildcard(socket.address) || !sameFamily(socket.address, requested.address) {
+		return false
+	}
+
+	// A socket of its own takes precedence over the wildcard socket
+	for _, other := range sockets {
+		if other.protocol == requested.protocol && other.port == requested.port && sameAddress(other.address, requested.address) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameAddress(a string, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB) && (ipA.To4() == nil) == (ipB.To4() == nil)
+}
+
+// isWildcard reports whether address is "*" or an unspecified address.
+func isWildcard(address string) bool {
+	if address == "*" {
+		return true
+	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// sameFamily reports whether a and b are both IPv4 or both IPv6 addresses.
+// ss prints "*" for a socket accepting both.
+func sameFamily(a string, b string) bool {
+	if a == "*" || b == "*" {
+		return true
+	}
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	return ipA != nil && ipB != nil && (ipA.To4() == nil) == (ipB.To4() == nil)
+}
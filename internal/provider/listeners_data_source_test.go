@@ -0,0 +1,42 @@
+package nginx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestListenersWithoutConfiguration(t *testing.T) {
+	ctx := context.Background()
+	d := &ListenersDataSource{client: newTestHost(t, map[string]string{
+		"ss":    "#!/bin/sh\necho 'tcp LISTEN 0 511 0.0.0.0:80 0.0.0.0:* users:((\"nginx\",pid=1,fd=6))'\n",
+		"nginx": "#!/bin/sh\necho 'nginx: [emerg] permission denied' >&2\nexit 1\n",
+	})}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	config := tfsdk.State{Schema: s}
+	if diags := config.Set(ctx, &ListenersDataSourceModel{}); diags.HasError() {
+		t.Fatalf("building the configuration: %v", diags)
+	}
+
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: s}}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 || resp.Diagnostics.Warnings()[0].Summary() != "Configuration Unavailable" {
+		t.Errorf("warnings = %v, want Configuration Unavailable", resp.Diagnostics.Warnings())
+	}
+
+	var data ListenersDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if len(data.Listeners) != 1 || data.Listeners[0].Port != types.Int64Value(80) || len(data.Listeners[0].Servers) != 0 {
+		t.Errorf("listeners = %v, want port 80 without servers", data.Listeners)
+	}
+}
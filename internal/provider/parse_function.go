@@ -0,0 +1,101 @@
+package nginx
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseFunction{}
+
+func NewParseFunction() function.Function {
+	return &ParseFunction{}
+}
+
+// ParseFunction defines the function implementation.
+type ParseFunction struct{}
+
+func (f *ParseFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse"
+}
+
+func (f *ParseFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses NGINX configuration",
+		MarkdownDescription: "Parses NGINX configuration into a list of directives. Every directive is an object " +
+			"with `name`, `args`, `line`, `block`, the list of nested directives or null for a simple directive, " +
+			"and `raw`, the verbatim body of blocks such as `content_by_lua_block` or null. Comments are left out.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "config",
+				MarkdownDescription: "The NGINX configuration to parse.",
+			},
+		},
+		Return: function.DynamicReturn{},
+	}
+}
+
+func (f *ParseFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var config string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &config))
+	if resp.Error != nil {
+		return
+	}
+
+	directives, err := parseConfig(config)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	tree, _ := directiveTree(stripComments(directives))
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, types.DynamicValue(tree)))
+}
+
+// directiveTree converts directives into a tuple of directive objects. The
+// types of nested blocks differ between directives, hence the tuple.
+func directiveTree(directives []*Directive) (attr.Value, attr.Type) {
+	elementTypes := make([]attr.Type, 0, len(directives))
+	elements := make([]attr.Value, 0, len(directives))
+
+	for _, d := range directives {
+		args := make([]attr.Value, 0, len(d.Args))
+		for _, arg := range d.Args {
+			args = append(args, types.StringValue(arg))
+		}
+
+		block, blockType := attr.Value(types.TupleNull(nil)), attr.Type(types.TupleType{})
+		if d.IsBlock && d.Raw == "" {
+			block, blockType = directiveTree(d.Block)
+		}
+
+		raw := types.StringNull()
+		if d.Raw != "" {
+			raw = types.StringValue(d.Raw)
+		}
+
+		attributeTypes := map[string]attr.Type{
+			"name":  types.StringType,
+			"args":  types.ListType{ElemType: types.StringType},
+			"line":  types.Int64Type,
+			"block": blockType,
+			"raw":   types.StringType,
+		}
+		elementTypes = append(elementTypes, types.ObjectType{AttrTypes: attributeTypes})
+		elements = append(elements, types.ObjectValueMust(attributeTypes, map[string]attr.Value{
+			"name":  types.StringValue(d.Name),
+			"args":  types.ListValueMust(types.StringType, args),
+			"line":  types.Int64Value(int64(d.Line)),
+			"block": block,
+			"raw":   raw,
+		}))
+	}
+
+	tupleType := types.TupleType{ElemTypes: elementTypes}
+	return types.TupleValueMust(elementTypes, elements), tupleType
+}
@@ -0,0 +1,375 @@
+package nginx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind identifies the kind of a lexical token in NGINX configuration.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenSemicolon
+	tokenOpenBrace
+	tokenCloseBrace
+	tokenComment
+)
+
+// token is a single lexical token.
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// lexer splits NGINX configuration into tokens, following the rules of
+// ngx_conf_read_token: words end at whitespace, ";" and "{" (except in
+// "${"), quotes only start at the beginning of a word, and "#" only starts
+// a comment at the beginning of a word.
+type lexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1}
+}
+
+func (l *lexer) next() (token, error) {
+	// Skip whitespace
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '\n' {
+			l.line++
+		} else if c != ' ' && c != '\t' && c != '\r' {
+			break
+		}
+		l.pos++
+	}
+
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, line: l.line}, nil
+	}
+
+	start := l.line
+	switch c := l.src[l.pos]; c {
+	case ';':
+		l.pos++
+		return token{kind: tokenSemicolon, value: ";", line: start}, nil
+	case '{':
+		l.pos++
+		return token{kind: tokenOpenBrace, value: "{", line: start}, nil
+	case '}':
+		l.pos++
+		return token{kind: tokenCloseBrace, value: "}", line: start}, nil
+	case '#':
+		end := strings.IndexByte(l.src[l.pos:], '\n')
+		if end < 0 {
+			end = len(l.src) - l.pos
+		}
+		comment := strings.TrimRight(l.src[l.pos+1:l.pos+end], "\r")
+		l.pos += end
+		return token{kind: tokenComment, value: comment, line: start}, nil
+	case '"', '\'':
+		return l.quotedWord(c)
+	}
+
+	return l.word()
+}
+
+// quotedWord reads a word enclosed in quote. The word ends at the closing
+// quote, so in `if ($a = "b")` the closing parenthesis is a word of its own,
+// as NGINX reads it.
+func (l *lexer) quotedWord(quote byte) (token, error) {
+	start := l.line
+	l.pos++
+
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return token{}, fmt.Errorf("line %d: unterminated quoted string", start)
+		}
+		c := l.src[l.pos]
+		if c == '\\' && l.pos+1 < len(l.src) {
+			b.WriteByte(c)
+			b.WriteByte(l.src[l.pos+1])
+			if l.src[l.pos+1] == '\n' {
+				l.line++
+			}
+			l.pos += 2
+			continue
+		}
+		if c == '\n' {
+			l.line++
+		}
+		l.pos++
+		if c == quote {
+			break
+		}
+		b.WriteByte(c)
+	}
+
+	return token{kind: tokenWord, value: unescape(b.String()), line: start}, nil
+}
+
+// word reads an unquoted word.
+func (l *lexer) word() (token, error) {
+	start := l.line
+
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '\\' && l.pos+1 < len(l.src) {
+			b.WriteByte(c)
+			b.WriteByte(l.src[l.pos+1])
+			l.pos += 2
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ';' {
+			break
+		}
+		if c == '{' && !(l.pos > 0 && l.src[l.pos-1] == '$') {
+			break
+		}
+		b.WriteByte(c)
+		l.pos++
+	}
+
+	return token{kind: tokenWord, value: unescape(b.String()), line: start}, nil
+}
+
+// rawBlock reads the body of a block whose content is not NGINX syntax, such
+// as the Lua code of content_by_lua_block, up to the matching closing brace.
+// Braces inside Lua strings and comments are ignored.
+func (l *lexer) rawBlock() (string, error) {
+	start := l.line
+	begin := l.pos
+	depth := 1
+
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == '"' || c == '\'':
+			l.skipLuaString(c)
+		case c == '[' && luaLongBracket(l.src[l.pos:]) >= 0:
+			l.skipLuaLongBracket(luaLongBracket(l.src[l.pos:]))
+		case strings.HasPrefix(l.src[l.pos:], "--"):
+			l.pos += 2
+			if level := luaLongBracket(l.src[l.pos:]); level >= 0 {
+				l.skipLuaLongBracket(level)
+				continue
+			}
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '{':
+			depth++
+			l.pos++
+		case c == '}':
+			depth--
+			l.pos++
+			if depth == 0 {
+				return l.src[begin : l.pos-1], nil
+			}
+		default:
+			l.pos++
+		}
+	}
+
+	return "", fmt.Errorf("line %d: unterminated block", start)
+}
+
+func (l *lexer) skipLuaString(quote byte) {
+	l.pos++
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		l.pos++
+		switch c {
+		case '\\':
+			l.pos++
+		case '\n':
+			l.line++
+		case quote:
+			return
+		}
+	}
+}
+
+// luaLongBracket returns the level of the Lua long bracket opening s, such
+// as 0 for "[[" and 2 for "[==[", or -1 when s does not start with one.
+func luaLongBracket(s string) int {
+	if !strings.HasPrefix(s, "[") {
+		return -1
+	}
+	level := 0
+	for level+1 < len(s) && s[level+1] == '=' {
+		level++
+	}
+	if level+1 < len(s) && s[level+1] == '[' {
+		return level
+	}
+	return -1
+}
+
+func (l *lexer) skipLuaLongBracket(level int) {
+	closing := "]" + strings.Repeat("=", level) + "]"
+	l.pos += level + 2
+	end := strings.Index(l.src[l.pos:], closing)
+	if end < 0 {
+		end = len(l.src) - l.pos
+	} else {
+		end += len(closing)
+	}
+	l.line += strings.Count(l.src[l.pos:l.pos+end], "\n")
+	l.pos += end
+}
+
+// unescape resolves the escape sequences NGINX recognises in words.
+func unescape(word string) string {
+	if !strings.Contains(word, `\`) {
+		return word
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(word); i++ {
+		if word[i] == '\\' && i+1 < len(word) {
+			switch word[i+1] {
+			case '"', '\'', '\\':
+				b.WriteByte(word[i+1])
+				i++
+				continue
+			case 't':
+				b.WriteByte('\t')
+				i++
+				continue
+			case 'r':
+				b.WriteByte('\r')
+				i++
+				continue
+			case 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(word[i])
+	}
+	return b.String()
+}
+
+// isRawBlockDirective reports whether the body of the named block directive
+// is foreign code rather than NGINX directives.
+func isRawBlockDirective(name string) bool {
+	return strings.HasSuffix(name, "_by_lua_block") || name == "lua_block"
+}
+
+// parseConfig parses NGINX configuration into a directive tree. Comments are
+// kept as directives with IsComment set. Includes are not followed; they are
+// returned as ordinary include directives.
+func parseConfig(src string) ([]*Directive, error) {
+	p := &parser{lexer: newLexer(src)}
+	return p.parseBlock(false)
+}
+
+type parser struct {
+	lexer *lexer
+}
+
+func (p *parser) parseBlock(nested bool) ([]*Directive, error) {
+	directives := []*Directive{}
+
+	for {
+		tok, err := p.lexer.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok.kind {
+		case tokenEOF:
+			if nested {
+				return nil, fmt.Errorf("line %d: unexpected end of file, expecting \"}\"", tok.line)
+			}
+			return directives, nil
+		case tokenCloseBrace:
+			if !nested {
+				return nil, fmt.Errorf("line %d: unexpected \"}\"", tok.line)
+			}
+			return directives, nil
+		case tokenComment:
+			directives = append(directives, &Directive{IsComment: true, Comment: tok.value, Line: tok.line})
+			continue
+		case tokenSemicolon, tokenOpenBrace:
+			return nil, fmt.Errorf("line %d: unexpected %q", tok.line, tok.value)
+		}
+
+		directive, err := p.parseDirective(tok)
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, directive)
+	}
+}
+
+func (p *parser) parseDirective(name token) (*Directive, error) {
+	directive := &Directive{Name: name.value, Line: name.line}
+
+	for {
+		tok, err := p.lexer.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok.kind {
+		case tokenWord:
+			directive.Args = append(directive.Args, tok.value)
+		case tokenComment:
+			// Comments between arguments carry no meaning
+		case tokenSemicolon:
+			return directive, nil
+		case tokenOpenBrace:
+			directive.IsBlock = true
+			if isRawBlockDirective(directive.Name) {
+				directive.Raw, err = p.lexer.rawBlock()
+				return directive, err
+			}
+			directive.Block, err = p.parseBlock(true)
+			return directive, err
+		case tokenCloseBrace:
+			return nil, fmt.Errorf("line %d: unexpected \"}\", expecting \";\" after %q", tok.line, directive.Name)
+		case tokenEOF:
+			return nil, fmt.Errorf("line %d: unexpected end of file, expecting \";\" or \"{\" after %q", tok.line, directive.Name)
+		}
+	}
+}
+
+// findDirectives returns the directives named name, searching nested blocks
+// depth-first in document order.
+func findDirectives(directives []*Directive, name string) []*Directive {
+	var found []*Directive
+	for _, d := range directives {
+		if d.IsComment {
+			continue
+		}
+		if d.Name == name {
+			found = append(found, d)
+		}
+		found = append(found, findDirectives(d.Block, name)...)
+	}
+	return found
+}
+
+// child returns the first direct child of d named name, or nil.
+func (d *Directive) child(name string) *Directive {
+	for _, c := range d.Block {
+		if !c.IsComment && c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package nginx
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/ssh"
+)
+
+// PerformanceModel describes the compression, caching and file serving
+// settings of a static site.
+type PerformanceModel struct {
+	Sendfile      types.Bool          `tfsdk:"sendfile"`
+	TCPNopush     types.Bool          `tfsdk:"tcp_nopush"`
+	ETag          types.Bool          `tfsdk:"etag"`
+	Compression   *CompressionModel   `tfsdk:"compression"`
+	CacheRules    []CacheRuleModel    `tfsdk:"cache_rules"`
+	OpenFileCache *OpenFileCacheModel `tfsdk:"open_file_cache"`
+}
+
+// CompressionModel describes gzip, and brotli where available.
+type CompressionModel struct {
+	Level     types.Int64    `tfsdk:"level"`
+	MinLength types.Int64    `tfsdk:"min_length"`
+	Types     []types.String `tfsdk:"types"`
+	Brotli    types.Bool     `tfsdk:"brotli"`
+}
+
+// CacheRuleModel sets caching headers for files with the given extensions.
+type CacheRuleModel struct {
+	Extensions   []types.String `tfsdk:"extensions"`
+	Expires      types.String   `tfsdk:"expires"`
+	CacheControl types.String   `tfsdk:"cache_control"`
+}
+
+// OpenFileCacheModel describes the open_file_cache directives.
+type OpenFileCacheModel struct {
+	Max      types.Int64  `tfsdk:"max"`
+	Inactive types.String `tfsdk:"inactive"`
+	Valid    types.String `tfsdk:"valid"`
+	MinUses  types.Int64  `tfsdk:"min_uses"`
+	Errors   types.Bool   `tfsdk:"errors"`
+}
+
+func performanceAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "Compression, caching and file serving settings rendered into the server block.",
+		Optional:            true,
+		Attributes: map[string]schema.Attribute{
+			"sendfile": schema.BoolAttribute{
+				MarkdownDescription: "Serve files with `sendfile`.",
+				Optional:            true,
+			},
+			"tcp_nopush": schema.BoolAttribute{
+				MarkdownDescription: "Send response headers and the start of a file in one packet.",
+				Optional:            true,
+			},
+			"etag": schema.BoolAttribute{
+				MarkdownDescription: "Send `ETag` headers for static files.",
+				Optional:            true,
+			},
+			"compression": schema.SingleNestedAttribute{
+				MarkdownDescription: "Enable gzip compression, and brotli when the module is available on the host.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"level": schema.Int64Attribute{
+						MarkdownDescription: "The compression level, from 1 to 9.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.Between(1, 9),
+						},
+					},
+					"min_length": schema.Int64Attribute{
+						MarkdownDescription: "The minimum response length, in bytes, that is compressed.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"types": schema.ListAttribute{
+						MarkdownDescription: "MIME types compressed in addition to `text/html`.",
+						ElementType:         types.StringType,
+						Optional:            true,
+					},
+					"brotli": schema.BoolAttribute{
+						MarkdownDescription: "Also enable brotli with the same settings. Skipped with a warning when the " +
+							"brotli module is neither built into NGINX on the host nor loaded with `load_module`.",
+						Optional: true,
+					},
+				},
+			},
+			"cache_rules": schema.ListNestedAttribute{
+				MarkdownDescription: "Cache-Control rules per file extension, each rendered as a regex location.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"extensions": schema.ListAttribute{
+							MarkdownDescription: "File extensions the rule applies to, without the leading dot.",
+							ElementType:         types.StringType,
+							Required:            true,
+							Validators: []validator.List{
+								listvalidator.SizeAtLeast(1),
+								listvalidator.ValueStringsAre(
+									stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9]+$`), "must be a file extension such as css"),
+								),
+							},
+						},
+						"expires": schema.StringAttribute{
+							MarkdownDescription: "The `expires` value, for example `30d`, `max` or `off`.",
+							Optional:            true,
+						},
+						"cache_control": schema.StringAttribute{
+							MarkdownDescription: "The `Cache-Control` header value, for example `public, immutable`.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"open_file_cache": schema.SingleNestedAttribute{
+				MarkdownDescription: "Cache open file descriptors and metadata.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"max": schema.Int64Attribute{
+						MarkdownDescription: "The maximum number of cached entries.",
+						Required:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"inactive": schema.StringAttribute{
+						MarkdownDescription: "Remove entries not accessed for this long, for example `20s`.",
+						Optional:            true,
+					},
+					"valid": schema.StringAttribute{
+						MarkdownDescription: "How often cached entries are revalidated, for example `30s`.",
+						Optional:            true,
+					},
+					"min_uses": schema.Int64Attribute{
+						MarkdownDescription: "The minimum number of accesses within `inactive` to keep an entry.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"errors": schema.BoolAttribute{
+						MarkdownDescription: "Also cache file lookup errors.",
+						Optional:            true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// directives renders the server-level performance directives. The brotli
+// directives are only rendered when the module is available on the host.
+func (p *PerformanceModel) directives(brotli bool) []*Directive {
+	if p == nil {
+		return nil
+	}
+
+	var directives []*Directive
+	if !p.Sendfile.IsNull() {
+		directives = append(directives, simpleDirective("sendfile", onOff(p.Sendfile.ValueBool())))
+	}
+	if !p.TCPNopush.IsNull() {
+		directives = append(directives, simpleDirective("tcp_nopush", onOff(p.TCPNopush.ValueBool())))
+	}
+	if !p.ETag.IsNull() {
+		directives = append(directives, simpleDirective("etag", onOff(p.ETag.ValueBool())))
+	}
+
+	if c := p.Compression; c != nil {
+		directives = append(directives, c.directives("gzip")...)
+		directives = append(directives, simpleDirective("gzip_vary", "on"))
+		if brotli && c.Brotli.ValueBool() {
+			directives = append(directives, c.directives("brotli")...)
+		}
+	}
+
+	if o := p.OpenFileCache; o != nil {
+		cache := simpleDirective("open_file_cache", "max="+strconv.FormatInt(o.Max.ValueInt64(), 10))
+		if !o.Inactive.IsNull() {
+			cache.Args = append(cache.Args, "inactive="+o.Inactive.ValueString())
+		}
+		directives = append(directives, cache)
+		if !o.Valid.IsNull() {
+			directives = append(directives, simpleDirective("open_file_cache_valid", o.Valid.ValueString()))
+		}
+		if !o.MinUses.IsNull() {
+			directives = append(directives, simpleDirective("open_file_cache_min_uses", strconv.FormatInt(o.MinUses.ValueInt64(), 10)))
+		}
+		if !o.Errors.IsNull() {
+			directives = append(directives, simpleDirective("open_file_cache_errors", onOff(o.Errors.ValueBool())))
+		}
+	}
+
+	return directives
+}
+
+// directives renders the compression directives for module, which is either
+// gzip or brotli; both take the same settings.
+func (c *CompressionModel) directives(module string) []*Directive {
+	directives := []*Directive{simpleDirective(module, "on")}
+	if !c.Level.IsNull() {
+		directives = append(directives, simpleDirective(module+"_comp_level", strconv.FormatInt(c.Level.ValueInt64(), 10)))
+	}
+	if !c.MinLength.IsNull() {
+		directives = append(directives, simpleDirective(module+"_min_length", strconv.FormatInt(c.MinLength.ValueInt64(), 10)))
+	}
+	if len(c.Types) > 0 {
+		mimeTypes := simpleDirective(module + "_types")
+		for _, t := range c.Types {
+			mimeTypes.Args = append(mimeTypes.Args, t.ValueString())
+		}
+		directives = append(directives, mimeTypes)
+	}
+	return directives
+}
+
+// cacheLocations renders a regex location per cache rule. Locations that
+// send a Cache-Control header repeat the inherited headers, as NGINX drops
+// them otherwise.
+func (p *PerformanceModel) cacheLocations(inherited []header) []*Directive {
+	if p == nil {
+		return nil
+	}
+
+	var locations []*Directive
+	for _, rule := range p.CacheRules {
+		extensions := make([]string, 0, len(rule.Extensions))
+		for _, extension := range rule.Extensions {
+			extensions = append(extensions, regexp.QuoteMeta(extension.ValueString()))
+		}
+
+		location := blockDirective("location", []string{"~*", `\.(` + strings.Join(extensions, "|") + `)$`})
+		if !rule.Expires.IsNull() {
+			location.Block = append(location.Block, simpleDirective("expires", rule.Expires.ValueString()))
+		}
+		if !rule.CacheControl.IsNull() {
+			headers := mergeHeaders(inherited, []header{{"Cache-Control", rule.CacheControl.ValueString(), false}})
+			location.Block = append(location.Block, headerDirectives(headers)...)
+		}
+		locations = append(locations, location)
+	}
+
+	return locations
+}
+
+// wantsBrotli reports whether the settings ask for brotli compression.
+func (p *PerformanceModel) wantsBrotli() bool {
+	return p != nil && p.Compression != nil && p.Compression.Brotli.ValueBool()
+}
+
+// hasBrotliModule reports whether the NGINX on the host was built with the
+// brotli module or loads it with load_module. Only --add-module counts from
+// the build options: a module built with --add-dynamic-module, like one
+// that is installed but not loaded, is only usable once load_module loads
+// it, since NGINX rejects the brotli directives until then.
+func hasBrotliModule(client *ssh.Client) (bool, error) {
+	command := "{ sudo nginx -V 2>&1 | grep -Eqi -- '--add-module=[^ ]*brotli' || sudo nginx -T 2>/dev/null | " +
+		"grep -Eqi '^[[:space:]]*load_module[[:space:]]+[^;]*brotli'; } && echo yes || echo no"
+
+	output, err := runCommand(client, command)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(string(output)) == "yes", nil
+}
+
+func onOff(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
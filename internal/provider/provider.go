@@ -8,19 +8,21 @@ import (
 	"os"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"golang.org/x/crypto/ssh"
 )
 
 // Ensure NginxProvider satisfies various provider interfaces.
 var _ provider.Provider = &NginxProvider{}
-
-//var _ provider.ProviderWithFunctions = &NginxProvider{}
+var _ provider.ProviderWithFunctions = &NginxProvider{}
 
 // NginxProvider defines the provider implementation.
 type NginxProvider struct {
@@ -35,6 +37,14 @@ type NginxProviderModel struct {
 	Host     types.String `tfsdk:"host"`
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
+
+	ConfigDirectories types.List `tfsdk:"config_directories"`
+}
+
+// resourceData is passed to the resources when the provider is configured.
+type resourceData struct {
+	client            *ssh.Client
+	configDirectories []string
 }
 
 func (p *NginxProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -55,6 +65,16 @@ func (p *NginxProvider) Schema(_ context.Context, req provider.SchemaRequest, re
 				Optional:  true,
 				Sensitive: true,
 			},
+			"config_directories": schema.ListAttribute{
+				MarkdownDescription: "The directories the resources may write configuration files to. Defaults to `" +
+					strings.Join(configPrefixes, "`, `") + "`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(pathValidator{}),
+				},
+			},
 		},
 	}
 }
@@ -112,6 +132,22 @@ func (p *NginxProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		)
 	}
 
+	// Directories are matched as path prefixes, so /etc/nginx must not
+	// allow /etc/nginx-old
+	directories := configPrefixes
+	if !config.ConfigDirectories.IsNull() && !config.ConfigDirectories.IsUnknown() {
+		var configured []string
+		resp.Diagnostics.Append(config.ConfigDirectories.ElementsAs(ctx, &configured, false)...)
+
+		directories = make([]string, 0, len(configured))
+		for _, directory := range configured {
+			if !strings.HasSuffix(directory, "/") {
+				directory += "/"
+			}
+			directories = append(directories, directory)
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -139,7 +175,10 @@ func (p *NginxProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	// Make the SSH client available during DataSource and Resource
 	// type Configure methods.
 	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.ResourceData = &resourceData{
+		client:            client,
+		configDirectories: directories,
+	}
 }
 
 func (p *NginxProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -148,12 +187,34 @@ func (p *NginxProvider) Resources(ctx context.Context) []func() resource.Resourc
 		NewSiteResource,
 		NewAPIResource,
 		NewProxyResource,
+		NewGeoResource,
+		NewRedirectResource,
+		NewTemplateFileResource,
+		NewDeprecatedConfigResource,
+		NewDeprecatedProxyResource,
 	}
 }
 
 func (p *NginxProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		NewExampleDataSource,
+		NewInfoDataSource,
+		NewFileDataSource,
+		NewConfigTestDataSource,
+		NewStatusDataSource,
+		NewCertificateDataSource,
+		NewListenersDataSource,
+		NewServersDataSource,
+	}
+}
+
+func (p *NginxProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewRenderServerFunction,
+		NewParseFunction,
+		NewFormatFunction,
+		NewEscapeArgFunction,
+		NewHtpasswdHashFunction,
+		NewValidateFunction,
 	}
 }
 
@@ -1,16 +1,20 @@
 package nginx
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/crypto/ssh"
@@ -19,33 +23,69 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ProxyResource{}
 var _ resource.ResourceWithImportState = &ProxyResource{}
+var _ resource.ResourceWithModifyPlan = &ProxyResource{}
+var _ resource.ResourceWithValidateConfig = &ProxyResource{}
+var _ resource.ResourceWithConfigValidators = &ProxyResource{}
+var _ resource.ResourceWithUpgradeState = &ProxyResource{}
+var _ resource.ResourceWithMoveState = &ProxyResource{}
 
 func NewProxyResource() resource.Resource {
-	return &ProxyResource{}
+	return &ProxyResource{typeName: "_proxy"}
+}
+
+// NewDeprecatedProxyResource returns the nginx_Proxy resource, the original
+// mixed-case name of nginx_proxy, kept as a deprecated alias.
+func NewDeprecatedProxyResource() resource.Resource {
+	return &ProxyResource{typeName: "_Proxy"}
 }
 
 // ProxyResource defines the resource implementation.
 type ProxyResource struct {
-	client interface{} // Use interface{} to accept SSH client passed from provider.go
+	client            interface{} // Use interface{} to accept SSH client passed from provider.go
+	configDirectories []string
+	typeName          string
 }
 
 // ProxyResourceModel describes the resource data model.
 type ProxyResourceModel struct {
-	ServerName types.String `tfsdk:"server_name"`
-	ListenPort types.Int64  `tfsdk:"listen_port"`
-	Root       types.String `tfsdk:"root"`
-	Path       types.String `tfsdk:"path"`
-	Content    types.String `tfsdk:"content"`
-	Id         types.String `tfsdk:"id"`
-	ProxyName  types.String `tfsdk:"proxy_name"`
+	ServerName      types.String           `tfsdk:"server_name"`
+	ListenPort      types.Int64            `tfsdk:"listen_port"`
+	Root            types.String           `tfsdk:"root"`
+	Path            types.String           `tfsdk:"path"`
+	Content         types.String           `tfsdk:"content"`
+	StoreContent    types.Bool             `tfsdk:"store_content"`
+	KeepOnDestroy   types.Bool             `tfsdk:"keep_on_destroy"`
+	ContentSHA256   types.String           `tfsdk:"content_sha256"`
+	Size            types.Int64            `tfsdk:"size"`
+	Mtime           types.String           `tfsdk:"mtime"`
+	Id              types.String           `tfsdk:"id"`
+	ProxyName       types.String           `tfsdk:"proxy_name"`
+	Access          *AccessModel           `tfsdk:"access"`
+	Headers         map[string]HeaderModel `tfsdk:"headers"`
+	SecurityHeaders *SecurityHeadersModel  `tfsdk:"security_headers"`
+	Directives      []ExtraDirectiveModel  `tfsdk:"directives"`
+}
+
+// serverBlock maps the model onto the shared server block renderer.
+func (m ProxyResourceModel) serverBlock() serverBlock {
+	return serverBlock{
+		ListenPort: m.ListenPort,
+		ServerName: m.ServerName,
+		Root:       m.Root,
+		Access:     m.Access,
+		Headers:    m.Headers,
+		Security:   m.SecurityHeaders,
+		Directives: m.Directives,
+	}
 }
 
 func (r *ProxyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_Proxy"
+	resp.TypeName = req.ProviderTypeName + r.typeName
 }
 
 func (r *ProxyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:             1,
 		MarkdownDescription: "Proxy resource",
 
 		Attributes: map[string]schema.Attribute{
@@ -56,24 +96,41 @@ func (r *ProxyResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"server_name": schema.StringAttribute{
 				MarkdownDescription: "The name of the server.",
 				Optional:            true,
+				Validators: []validator.String{
+					serverNameValidator{},
+				},
 			},
 			"listen_port": schema.Int64Attribute{
 				MarkdownDescription: "The port the Proxy listens on.",
 				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
 			},
 			"root": schema.StringAttribute{
 				MarkdownDescription: "The root directory of the Proxy.",
 				Optional:            true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
 			},
 			"path": schema.StringAttribute{
 				MarkdownDescription: "The path of the Proxy Proxyuration file.",
-				Optional:            true,
-			},
-			"content": schema.StringAttribute{
-				MarkdownDescription: "The content of the Proxy.",
-				Computed:            true,
-				Optional:            true,
+				Required:            true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
 			},
+			"content":          contentAttribute(serverAttributes),
+			"store_content":    storeContentAttribute(),
+			"keep_on_destroy":  keepOnDestroyAttribute(),
+			"content_sha256":   contentSHA256Attribute(),
+			"size":             sizeAttribute(),
+			"mtime":            mtimeAttribute(),
+			"access":           accessAttribute("server"),
+			"headers":          headersAttribute("server"),
+			"security_headers": securityHeadersAttribute(),
+			"directives":       extraDirectivesAttribute("at the end of the server block"),
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The ID of the Proxy resource.",
@@ -83,6 +140,122 @@ func (r *ProxyResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 		},
 	}
+
+	if r.typeName == "_Proxy" {
+		resp.Schema.DeprecationMessage = "Use nginx_proxy instead. Existing resources move to it with a moved block, " +
+			"without being destroyed. nginx_Proxy will be removed in a future version."
+	}
+}
+
+// ValidateConfig checks the extra directives and the content set by the
+// user against the directive catalog.
+func (r *ProxyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ProxyResourceModel
+
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateExtraDirectives(data.Directives, contextServer, path.Root("directives"))...)
+	resp.Diagnostics.Append(validateContent(data.Content, path.Root("content"))...)
+}
+
+// ConfigValidators requires the listen port and server name to be set
+// together, since one is rarely useful without the other.
+func (r *ProxyResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("listen_port"),
+			path.MatchRoot("server_name"),
+		),
+	}
+}
+
+// UpgradeState upgrades state from version 0, which predates the structured
+// attributes and checksums. The content it held is dropped, since content is
+// only kept in state on request; the next refresh records the checksum of
+// the file instead.
+func (r *ProxyResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: serverSchemaV0("proxy_name"),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				upgraded, diags := upgradeProxyStateV0(ctx, req.State)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &upgraded)...)
+			},
+		},
+	}
+}
+
+// MoveState moves nginx_Proxy resources, at either schema version, to
+// nginx_proxy through moved blocks.
+func (r *ProxyResource) MoveState(ctx context.Context) []resource.StateMover {
+	if r.typeName != "_proxy" {
+		return nil
+	}
+
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+
+	return []resource.StateMover{
+		{
+			SourceSchema: &current.Schema,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "nginx_Proxy" || req.SourceSchemaVersion != 1 || req.SourceState == nil {
+					return
+				}
+
+				// Both types share the schema, so the state carries over as is
+				resp.TargetState.Raw = req.SourceState.Raw
+			},
+		},
+		{
+			SourceSchema: serverSchemaV0("proxy_name"),
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "nginx_Proxy" || req.SourceSchemaVersion != 0 || req.SourceState == nil {
+					return
+				}
+
+				moved, diags := upgradeProxyStateV0(ctx, req.SourceState)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &moved)...)
+			},
+		},
+	}
+}
+
+// upgradeProxyStateV0 converts version 0 state into the current model.
+func upgradeProxyStateV0(ctx context.Context, state *tfsdk.State) (ProxyResourceModel, diag.Diagnostics) {
+	prior, diags := readServerStateV0(ctx, state, "proxy_name")
+
+	return ProxyResourceModel{
+		ProxyName:     prior.Name,
+		ServerName:    prior.ServerName,
+		ListenPort:    prior.ListenPort,
+		Root:          prior.Root,
+		Path:          prior.Path,
+		Content:       types.StringNull(),
+		StoreContent:  types.BoolValue(false),
+		KeepOnDestroy: types.BoolValue(false),
+		ContentSHA256: types.StringNull(),
+		Size:          types.Int64Null(),
+		Mtime:         types.StringNull(),
+		Id:            prior.Id,
+	}, diags
 }
 
 func (r *ProxyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -91,17 +264,48 @@ func (r *ProxyResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*ssh.Client) // Type assertion to retrieve the SSH client
+	data, ok := req.ProviderData.(*resourceData) // Type assertion to retrieve the SSH client
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.configDirectories = data.configDirectories
+}
+
+// ModifyPlan renders the planned configuration to plan its checksum and
+// show the change it makes to the file on the host.
+func (r *ProxyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Configuration files may only be written below the configured directories
+	resp.Diagnostics.Append(checkConfigPath(ctx, req.Plan, r.configDirectories)...)
+
+	// Nothing to render on destroy or while the configuration is not known
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() || r.client == nil {
+		return
+	}
+
+	var plan ProxyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, diags := fileContent(ctx, req.Config, func() string {
+		return renderConfig(plan.serverBlock().directives())
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planFile(ctx, req, resp, content)
+	if planChanges(ctx, req, resp, "content_sha256") {
+		resp.Diagnostics.Append(planDiff(r.client.(*ssh.Client), plan.Path.ValueString(), content, false)...)
+	}
 }
 
 func (r *ProxyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -113,48 +317,41 @@ func (r *ProxyResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	// Build the NGINX server block content
-	ProxyContent := fmt.Sprintf(`
-	server {
-		listen %d;
-		server_name %s;
-
-		root %s;
-		index index.html;
-
-		location / {
-			try_files $uri $uri/ =404;
-		}
-	}`, data.ListenPort.ValueInt64(), data.ServerName.ValueString(), data.Root.ValueString())
+	// Use the configured content verbatim, or build the NGINX server block
+	ProxyContent, diags := fileContent(ctx, req.Config, func() string {
+		return renderConfig(data.serverBlock().directives())
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Use SSH to write the content to the file
+	// Write the configuration, keeping the previous file if NGINX rejects it
 	sshClient := r.client.(*ssh.Client)
-
-	session, err := sshClient.NewSession()
-	if err != nil {
+	if err := applyConfigFile(sshClient, data.Path.ValueString(), ProxyContent); err != nil {
 		resp.Diagnostics.AddError(
-			"SSH Session Error",
-			fmt.Sprintf("Failed to create SSH session: %s", err),
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", data.Path.ValueString(), err),
 		)
 		return
 	}
-	defer session.Close()
-
-	command := fmt.Sprintf("echo '%s' | sudo tee %s > /dev/null", shellEscape(ProxyContent), data.Path.ValueString())
 
-	if err := session.Run(command); err != nil {
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Command Execution Error",
-			fmt.Sprintf("Failed to execute command: %s", err),
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
 		)
 		return
 	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
 
 	// Set the resource ID to the Proxy_name
 	data.Id = types.StringValue(data.ProxyName.ValueString())
 
-	// Explicitly set the content
-	data.Content = types.StringValue(ProxyContent)
+	// Keep the content in state only when requested
+	data.Content = appliedContent(data.Content, data.StoreContent, ProxyContent)
 
 	// Save the data into the Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -174,62 +371,58 @@ func (r *ProxyResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// Use SSH client to verify the file existence and retrieve its content
-	sshClient := r.client.(*ssh.Client)
-	session, err := sshClient.NewSession()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"SSH Session Error",
-			fmt.Sprintf("Failed to create SSH session: %s", err),
-		)
-		return
+	// State written before keep_on_destroy existed has no value for it
+	if data.KeepOnDestroy.IsNull() {
+		data.KeepOnDestroy = types.BoolValue(false)
 	}
-	defer session.Close()
 
-	// Command to check file existence and read content
-	checkCommand := fmt.Sprintf("if [ -f %s ]; then cat %s; else echo 'NOT_FOUND'; fi", data.Path.ValueString(), data.Path.ValueString())
-	stdout, err := session.StdoutPipe()
+	// Use SSH client to verify the file existence and retrieve its content
+	sshClient := r.client.(*ssh.Client)
+	remote, found, err := readRemoteFile(sshClient, data.Path.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"SSH Pipe Error",
-			fmt.Sprintf("Failed to create stdout pipe: %s", err),
-		)
-		return
-	}
-
-	if err := session.Start(checkCommand); err != nil {
-		resp.Diagnostics.AddError(
-			"SSH Command Execution Error",
-			fmt.Sprintf("Failed to execute command: %s", err),
-		)
-		return
-	}
-
-	// Read the command output
-	var result strings.Builder
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
-		result.WriteString(scanner.Text() + "\n")
-	}
-
-	if err := session.Wait(); err != nil {
-		resp.Diagnostics.AddError(
-			"SSH Command Error",
-			fmt.Sprintf("Failed to complete command: %s", err),
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read %s: %s", data.Path.ValueString(), err),
 		)
 		return
 	}
 
 	// Handle 'NOT_FOUND' scenario
-	if strings.TrimSpace(result.String()) == "NOT_FOUND" {
+	if !found {
 		resp.Diagnostics.AddWarning(
 			"File Not Found",
 			fmt.Sprintf("The file at path '%s' does not exist.", data.Path.ValueString()),
 		)
 		data.Content = types.StringNull()
 	} else {
-		data.Content = types.StringValue(result.String())
+		// Refresh the typed attributes from the server block on the host,
+		// unless the content is written verbatim and has none
+		if data.Content.IsNull() || !data.serverBlock().empty() {
+			settings, ok, diags := refreshServer(data.serverBlock().directives(), remote)
+			resp.Diagnostics.Append(diags...)
+			if ok {
+				data.ServerName = settings.serverName(data.ServerName)
+				data.ListenPort = settings.ListenPort
+				data.Root = settings.Root
+			}
+		}
+
+		// Keep the stored content unless the host holds a different configuration
+		if !data.Content.IsNull() {
+			data.Content = types.StringValue(refreshContent(data.Content.ValueString(), remote))
+		}
+	}
+
+	// Refresh the checksum, which plans a rewrite when the file changed
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
 	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
 
 	// Ensure the ID remains consistent
 	data.Id = types.StringValue(data.ProxyName.ValueString())
@@ -254,47 +447,41 @@ func (r *ProxyResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// Build the updated NGINX Proxyuration
-	updatedProxy := fmt.Sprintf(`
-	server {
-		listen %d;
-		server_name %s;
-
-		root %s;
-		index index.html;
-
-		location / {
-			try_files $uri $uri/ =404;
-		}
-	}`, plan.ListenPort.ValueInt64(), plan.ServerName.ValueString(), plan.Root.ValueString())
+	// Use the configured content verbatim, or build the updated NGINX configuration
+	updatedProxy, diags := fileContent(ctx, req.Config, func() string {
+		return renderConfig(plan.serverBlock().directives())
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Use SSH to update the file content
+	// Write the configuration, keeping the previous file if NGINX rejects it
 	sshClient := r.client.(*ssh.Client)
-	session, err := sshClient.NewSession()
-	if err != nil {
+	if err := applyConfigFile(sshClient, plan.Path.ValueString(), updatedProxy); err != nil {
 		resp.Diagnostics.AddError(
-			"SSH Session Error",
-			fmt.Sprintf("Failed to create SSH session: %s", err),
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", plan.Path.ValueString(), err),
 		)
 		return
 	}
-	defer session.Close()
-
-	command := fmt.Sprintf("echo '%s' | sudo tee %s > /dev/null", shellEscape(updatedProxy), plan.Path.ValueString())
 
-	if err := session.Run(command); err != nil {
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, plan.Path.ValueString())
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Command Execution Error",
-			fmt.Sprintf("Failed to execute command: %s", err),
+			fmt.Sprintf("Failed to read the checksum of %s: %s", plan.Path.ValueString(), err),
 		)
 		return
 	}
+	plan.ContentSHA256, plan.Size, plan.Mtime = fileAttributes(info, found)
 
 	// Set the resource ID to the stable Proxy_name
 	plan.Id = types.StringValue(plan.ProxyName.ValueString())
 
-	// Set the content to the updated Proxyuration
-	plan.Content = types.StringValue(updatedProxy)
+	// Keep the content in state only when requested
+	plan.Content = appliedContent(plan.Content, plan.StoreContent, updatedProxy)
 
 	// Save the updated state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -310,20 +497,63 @@ func (r *ProxyResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete Proxy, got error: %s", err))
-	//     return
-	// }
+	if data.KeepOnDestroy.ValueBool() {
+		tflog.Trace(ctx, fmt.Sprintf("Kept configuration file of Proxy resource on the host: %s", data.Path.ValueString()))
+		return
+	}
+
+	// Remove the configuration file, then validate and reload NGINX
+	found, err := deleteConfigFile(r.client.(*ssh.Client), data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to delete file at %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	if !found {
+		tflog.Trace(ctx, fmt.Sprintf("Configuration file already removed from the host: %s", data.Path.ValueString()))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Deleted Proxy resource: %s", data.ProxyName.ValueString()))
 }
 
 func (r *ProxyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Import by name and path
+	idParts := strings.Split(req.ID, ":")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"Expected import ID in format 'proxy_name:path'",
+		)
+		return
+	}
+
+	// Reconstruct the typed attributes from the configuration on the host
+	imported, diags := importServer(r.client.(*ssh.Client), idParts[1], false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data := ProxyResourceModel{
+		ProxyName:     types.StringValue(idParts[0]),
+		Path:          types.StringValue(idParts[1]),
+		Id:            types.StringValue(idParts[0]),
+		ServerName:    imported.Settings.ServerName,
+		ListenPort:    imported.Settings.ListenPort,
+		Root:          imported.Settings.Root,
+		Access:        imported.Access,
+		Headers:       imported.Headers,
+		Directives:    imported.Directives,
+		Content:       types.StringNull(),
+		StoreContent:  types.BoolValue(false),
+		KeepOnDestroy: types.BoolValue(false),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
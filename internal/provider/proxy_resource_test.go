@@ -0,0 +1,88 @@
+package nginx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestProxyUpdateWithoutStoredContent(t *testing.T) {
+	ctx := context.Background()
+	r := &ProxyResource{client: newTestHost(t, nil)}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	file := filepath.Join(t.TempDir(), "example")
+	model := ProxyResourceModel{
+		ProxyName:     types.StringValue("example"),
+		ServerName:    types.StringValue("example.com"),
+		ListenPort:    types.Int64Value(8080),
+		Root:          types.StringNull(),
+		Path:          types.StringValue(file),
+		Content:       types.StringNull(),
+		StoreContent:  types.BoolValue(false),
+		KeepOnDestroy: types.BoolValue(false),
+		ContentSHA256: types.StringNull(),
+		Size:          types.Int64Null(),
+		Mtime:         types.StringNull(),
+		Id:            types.StringNull(),
+	}
+
+	config := tfsdk.Plan{Schema: s}
+	if diags := config.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("building the configuration: %v", diags)
+	}
+
+	model.Content = types.StringUnknown()
+	model.ContentSHA256 = types.StringUnknown()
+	model.Size = types.Int64Unknown()
+	model.Mtime = types.StringUnknown()
+	model.Id = types.StringValue("example")
+	plan := tfsdk.Plan{Schema: s}
+	if diags := plan.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("building the plan: %v", diags)
+	}
+
+	model.ListenPort = types.Int64Value(80)
+	model.Content = types.StringNull()
+	model.ContentSHA256 = types.StringValue(contentChecksum(""))
+	model.Size = types.Int64Value(0)
+	model.Mtime = types.StringValue("2024-01-01T00:00:00Z")
+	state := tfsdk.State{Schema: s}
+	if diags := state.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("building the state: %v", diags)
+	}
+
+	resp := resource.UpdateResponse{State: tfsdk.State{Schema: s, Raw: state.Raw}}
+	r.Update(ctx, resource.UpdateRequest{
+		Config: tfsdk.Config{Schema: s, Raw: config.Raw},
+		Plan:   plan,
+		State:  state,
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+
+	written, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("reading the written file: %s", err)
+	}
+
+	var content, sha types.String
+	resp.State.GetAttribute(ctx, path.Root("content"), &content)
+	resp.State.GetAttribute(ctx, path.Root("content_sha256"), &sha)
+	if !content.IsNull() {
+		t.Errorf("content = %s, want null without store_content", content)
+	}
+	if want := contentChecksum(string(written)); sha.ValueString() != want {
+		t.Errorf("content_sha256 = %s, want %s", sha.ValueString(), want)
+	}
+}
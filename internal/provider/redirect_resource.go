@@ -0,0 +1,599 @@
+package nginx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &RedirectResource{}
+var _ resource.ResourceWithImportState = &RedirectResource{}
+var _ resource.ResourceWithValidateConfig = &RedirectResource{}
+var _ resource.ResourceWithModifyPlan = &RedirectResource{}
+var _ resource.ResourceWithConfigValidators = &RedirectResource{}
+
+// variableUnsafeChars matches the characters that may not appear in an NGINX
+// variable name.
+var variableUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func NewRedirectResource() resource.Resource {
+	return &RedirectResource{}
+}
+
+// RedirectResource defines the resource implementation.
+type RedirectResource struct {
+	client            interface{} // Use interface{} to accept SSH client passed from provider.go
+	configDirectories []string
+}
+
+// RedirectResourceModel describes the resource data model.
+type RedirectResourceModel struct {
+	RedirectName  types.String          `tfsdk:"redirect_name"`
+	SourceHosts   []types.String        `tfsdk:"source_hosts"`
+	ListenPort    types.Int64           `tfsdk:"listen_port"`
+	Rules         []RedirectRuleModel   `tfsdk:"rules"`
+	Target        types.String          `tfsdk:"target"`
+	PreservePath  types.Bool            `tfsdk:"preserve_path"`
+	PreserveQuery types.Bool            `tfsdk:"preserve_query"`
+	StatusCode    types.Int64           `tfsdk:"status_code"`
+	Directives    []ExtraDirectiveModel `tfsdk:"directives"`
+	Path          types.String          `tfsdk:"path"`
+	Content       types.String          `tfsdk:"content"`
+	ContentSHA256 types.String          `tfsdk:"content_sha256"`
+	Size          types.Int64           `tfsdk:"size"`
+	Mtime         types.String          `tfsdk:"mtime"`
+	KeepOnDestroy types.Bool            `tfsdk:"keep_on_destroy"`
+	Id            types.String          `tfsdk:"id"`
+}
+
+// RedirectRuleModel redirects a single request path.
+type RedirectRuleModel struct {
+	Source types.String `tfsdk:"source"`
+	Target types.String `tfsdk:"target"`
+}
+
+// variable returns the map variable holding the redirect target. It is
+// derived from the resource name because map variables share the http
+// context with every other redirect. Replacing the unsafe characters can
+// make names such as a-b and a_b collide, so a hash of the name itself is
+// appended.
+func (m RedirectResourceModel) variable() string {
+	name := m.RedirectName.ValueString()
+	sum := sha256.Sum256([]byte(name))
+	return "$redirect_target_" + variableUnsafeChars.ReplaceAllString(name, "_") + "_" + hex.EncodeToString(sum[:4])
+}
+
+// directives renders the redirect table and the server block answering for
+// the source hosts. Rules are looked up by exact $request_uri first; a regex
+// entry per rule catches the same path with a query string attached.
+func (m RedirectResourceModel) directives() []*Directive {
+	variable := m.variable()
+	code := strconv.FormatInt(m.StatusCode.ValueInt64(), 10)
+
+	var args string
+	if m.PreserveQuery.ValueBool() {
+		args = "$is_args$args"
+	}
+
+	server := blockDirective("server", nil,
+		simpleDirective("listen", strconv.FormatInt(m.ListenPort.ValueInt64(), 10)),
+	)
+	server.Block = append(server.Block, simpleDirective("server_name", serverNameArgs(stringValues(m.SourceHosts))...))
+	server.Block = append(server.Block, extraDirectives(m.Directives)...)
+
+	var directives []*Directive
+	if len(m.Rules) > 0 {
+		table := blockDirective("map", []string{"$request_uri", variable},
+			simpleDirective("default", ""),
+		)
+		for _, rule := range m.Rules {
+			table.Block = append(table.Block, simpleDirective(rule.Source.ValueString(), rule.Target.ValueString()))
+		}
+		for _, rule := range m.Rules {
+			pattern := "~^" + regexp.QuoteMeta(rule.Source.ValueString()) + `\?`
+			table.Block = append(table.Block, simpleDirective(pattern, rule.Target.ValueString()))
+		}
+		directives = append(directives, table)
+
+		server.Block = append(server.Block, blockDirective("if", []string{"(" + variable + ")"},
+			simpleDirective("return", code, variable+args),
+		))
+	}
+
+	if m.Target.IsNull() {
+		server.Block = append(server.Block, simpleDirective("return", "404"))
+	} else {
+		// $uri and $request_uri start with a slash of their own
+		target := m.Target.ValueString()
+		switch {
+		case m.PreservePath.ValueBool() && m.PreserveQuery.ValueBool():
+			target = strings.TrimRight(target, "/") + "$request_uri"
+		case m.PreservePath.ValueBool():
+			target = strings.TrimRight(target, "/") + "$uri"
+		default:
+			target += args
+		}
+		server.Block = append(server.Block, simpleDirective("return", code, target))
+	}
+
+	return append(directives, server)
+}
+
+func (r *RedirectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_redirect"
+}
+
+func (r *RedirectResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Redirect resource. Renders a `map`-driven redirect table and a server block for domain and path moves.",
+
+		Attributes: map[string]schema.Attribute{
+			"redirect_name": schema.StringAttribute{
+				MarkdownDescription: "A unique name for the redirect resource.",
+				Required:            true,
+			},
+			"source_hosts": schema.ListAttribute{
+				MarkdownDescription: "The host names being redirected away from.",
+				ElementType:         types.StringType,
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.UniqueValues(),
+					listvalidator.ValueStringsAre(serverNameValidator{}),
+				},
+			},
+			"listen_port": schema.Int64Attribute{
+				MarkdownDescription: "The port the redirect server listens on. Defaults to `80`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(80),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+			},
+			"rules": schema.ListNestedAttribute{
+				MarkdownDescription: "Path redirects, looked up in a `map` on `$request_uri`.",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"source": schema.StringAttribute{
+							MarkdownDescription: "The request path to redirect, for example `/old-page`.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^/`), "must be a path starting with /"),
+							},
+						},
+						"target": schema.StringAttribute{
+							MarkdownDescription: "The path or absolute URL to redirect to.",
+							Required:            true,
+						},
+					},
+				},
+			},
+			"target": schema.StringAttribute{
+				MarkdownDescription: "Where requests not matched by a rule are redirected, for example `https://new.example.com`. " +
+					"When omitted, unmatched requests get a 404.",
+				Optional: true,
+			},
+			"preserve_path": schema.BoolAttribute{
+				MarkdownDescription: "Append the request path to `target`. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"preserve_query": schema.BoolAttribute{
+				MarkdownDescription: "Carry the query string over to the redirect target. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"status_code": schema.Int64Attribute{
+				MarkdownDescription: "The redirect status code. Defaults to `301`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(301),
+				Validators: []validator.Int64{
+					int64validator.OneOf(301, 302, 303, 307, 308),
+				},
+			},
+			"directives": extraDirectivesAttribute("in the server block before the redirects"),
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The path of the redirect configuration file.",
+				Required:            true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "The rendered redirect configuration.",
+				Computed:            true,
+			},
+			"content_sha256":  contentSHA256Attribute(),
+			"size":            sizeAttribute(),
+			"mtime":           mtimeAttribute(),
+			"keep_on_destroy": keepOnDestroyAttribute(),
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ID of the redirect resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RedirectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data RedirectResourceModel
+
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hosts := make(map[string]bool, len(data.SourceHosts))
+	for _, host := range data.SourceHosts {
+		hosts[strings.ToLower(host.ValueString())] = true
+	}
+
+	// Reject sources listed more than once
+	targets := make(map[string]string, len(data.Rules))
+	for i, rule := range data.Rules {
+		source := rule.Source.ValueString()
+		if previous, ok := targets[source]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rules").AtListIndex(i).AtName("source"),
+				"Conflicting Redirect Source",
+				fmt.Sprintf("The source %q is already redirected to %q.", source, previous),
+			)
+			continue
+		}
+		targets[source] = rule.Target.ValueString()
+	}
+
+	// Follow every rule through the table and reject chains that come back
+	// to a path they already visited
+	for i, rule := range data.Rules {
+		visited := map[string]bool{rule.Source.ValueString(): true}
+		next, local := localRedirectPath(rule.Target.ValueString(), hosts)
+		for local {
+			if visited[next] {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("rules").AtListIndex(i),
+					"Redirect Loop",
+					fmt.Sprintf("The redirect from %q leads back to %q.", rule.Source.ValueString(), next),
+				)
+				break
+			}
+			visited[next] = true
+
+			target, ok := targets[next]
+			if !ok {
+				break
+			}
+			next, local = localRedirectPath(target, hosts)
+		}
+	}
+
+	// A catch-all target on one of the source hosts redirects to itself
+	if !data.Target.IsNull() {
+		if _, local := localRedirectPath(data.Target.ValueString(), hosts); local {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("target"),
+				"Redirect Loop",
+				fmt.Sprintf("The target %q is served by this redirect's own source hosts.", data.Target.ValueString()),
+			)
+		}
+	}
+
+	resp.Diagnostics.Append(validateExtraDirectives(data.Directives, contextServer, path.Root("directives"))...)
+}
+
+// localRedirectPath returns the path a redirect target resolves to when it
+// stays on one of the source hosts. The boolean result is false for targets
+// that leave the redirect server.
+func localRedirectPath(target string, hosts map[string]bool) (string, bool) {
+	if strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "//") {
+		return strings.SplitN(target, "?", 2)[0], true
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" || !hosts[strings.ToLower(u.Hostname())] {
+		return "", false
+	}
+
+	if u.Path == "" {
+		return "/", true
+	}
+	return u.Path, true
+}
+
+// ConfigValidators requires rules or a fallback target, without which every
+// request would get a 404.
+func (r *RedirectResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("rules"),
+			path.MatchRoot("target"),
+		),
+	}
+}
+
+func (r *RedirectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Use the SSH client passed from the provider
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData) // Type assertion to retrieve the SSH client
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.configDirectories = data.configDirectories
+}
+
+// ModifyPlan renders the planned configuration into content and shows the
+// change it makes to the file on the host.
+func (r *RedirectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Configuration files may only be written below the configured directories
+	resp.Diagnostics.Append(checkConfigPath(ctx, req.Plan, r.configDirectories)...)
+
+	// Nothing to render on destroy or while the configuration is not known
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() || r.client == nil {
+		return
+	}
+
+	var plan RedirectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keep the stored content when it only differs in formatting
+	content := renderConfig(plan.directives())
+	if plan.Content.IsUnknown() || !equivalentConfig(plan.Content.ValueString(), content) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), content)...)
+	}
+
+	planChecksum(ctx, resp, content)
+	if planChanges(ctx, req, resp, "content_sha256") {
+		resp.Diagnostics.Append(planDiff(r.client.(*ssh.Client), plan.Path.ValueString(), content, false)...)
+	}
+}
+
+func (r *RedirectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RedirectResourceModel
+
+	// Retrieve the plan data
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content := renderConfig(data.directives())
+
+	// Write the configuration, keeping the previous file if NGINX rejects it
+	sshClient := r.client.(*ssh.Client)
+	if err := applyConfigFile(sshClient, data.Path.ValueString(), content); err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
+
+	data.Id = types.StringValue(data.RedirectName.ValueString())
+	data.Content = types.StringValue(content)
+
+	// Save the data into the Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Created redirect resource: %s", data.RedirectName.ValueString()))
+}
+
+func (r *RedirectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RedirectResourceModel
+
+	// Get the current state from Terraform
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// State written before keep_on_destroy existed has no value for it
+	if data.KeepOnDestroy.IsNull() {
+		data.KeepOnDestroy = types.BoolValue(false)
+	}
+
+	sshClient := r.client.(*ssh.Client)
+	content, found, err := readRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read redirect configuration from %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// Handle missing file case
+	if !found {
+		resp.Diagnostics.AddWarning(
+			"Resource Not Found",
+			fmt.Sprintf("The file at path '%s' does not exist. Terraform will remove it from the state.", data.Path.ValueString()),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Keep the stored content unless the host holds a different configuration
+	data.Content = types.StringValue(refreshContent(data.Content.ValueString(), content))
+
+	// Refresh the checksum, which plans a rewrite once the file was edited
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
+
+	// Save the updated state back to Terraform
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RedirectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan RedirectResourceModel
+	var state RedirectResourceModel
+
+	// Retrieve the updated plan data
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Retrieve the current state data
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content := renderConfig(plan.directives())
+
+	// Write the configuration, keeping the previous file if NGINX rejects it
+	sshClient := r.client.(*ssh.Client)
+	if err := applyConfigFile(sshClient, plan.Path.ValueString(), content); err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", plan.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, plan.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", plan.Path.ValueString(), err),
+		)
+		return
+	}
+	plan.ContentSHA256, plan.Size, plan.Mtime = fileAttributes(info, found)
+
+	// Remove the previous file when the path moved
+	if state.Path.ValueString() != plan.Path.ValueString() {
+		if _, err := deleteConfigFile(sshClient, state.Path.ValueString()); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Stale Configuration File",
+				fmt.Sprintf("Failed to delete previous file at %s: %s", state.Path.ValueString(), err),
+			)
+		}
+	}
+
+	plan.Id = types.StringValue(plan.RedirectName.ValueString())
+	plan.Content = types.StringValue(content)
+
+	// Save the updated state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Updated redirect resource: %s", plan.RedirectName.ValueString()))
+}
+
+func (r *RedirectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RedirectResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.KeepOnDestroy.ValueBool() {
+		tflog.Trace(ctx, fmt.Sprintf("Kept configuration file of redirect resource on the host: %s", data.Path.ValueString()))
+		return
+	}
+
+	// Remove the configuration file, then validate and reload NGINX
+	found, err := deleteConfigFile(r.client.(*ssh.Client), data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to delete file at %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	if !found {
+		tflog.Trace(ctx, fmt.Sprintf("Configuration file already removed from the host: %s", data.Path.ValueString()))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Deleted redirect resource: %s", data.RedirectName.ValueString()))
+}
+
+func (r *RedirectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by name and path
+	idParts := strings.Split(req.ID, ":")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"Expected import ID in format 'redirect_name:path'",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("redirect_name"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("keep_on_destroy"), false)...)
+}
@@ -0,0 +1,49 @@
+package nginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestRedirectVariableUnique(t *testing.T) {
+	a := RedirectResourceModel{RedirectName: types.StringValue("a-b")}.variable()
+	b := RedirectResourceModel{RedirectName: types.StringValue("a_b")}.variable()
+	if a == b {
+		t.Fatalf("redirects a-b and a_b share the map variable %s", a)
+	}
+	if !strings.HasPrefix(a, "$redirect_target_a_b_") {
+		t.Errorf("unexpected variable %s", a)
+	}
+}
+
+func TestRedirectPreservePath(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		preserveQuery bool
+		want          string
+	}{
+		{"path", "https://example.com", false, "return 301 https://example.com$uri;"},
+		{"trailing slash", "https://example.com/", false, "return 301 https://example.com$uri;"},
+		{"query", "https://example.com/", true, "return 301 https://example.com$request_uri;"},
+		{"nested", "https://example.com/new/", false, "return 301 https://example.com/new$uri;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := RedirectResourceModel{
+				RedirectName:  types.StringValue("test"),
+				ListenPort:    types.Int64Value(80),
+				Target:        types.StringValue(tt.target),
+				PreservePath:  types.BoolValue(true),
+				PreserveQuery: types.BoolValue(tt.preserveQuery),
+				StatusCode:    types.Int64Value(301),
+			}
+			if got := renderConfig(m.directives()); !strings.Contains(got, tt.want) {
+				t.Errorf("rendered configuration lacks %q:\n%s", tt.want, got)
+			}
+		})
+	}
+}
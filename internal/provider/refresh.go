@@ -0,0 +1,226 @@
+package nginx
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// serverSettings holds the typed attributes Read refreshes from the server
+// block on the host.
+type serverSettings struct {
+	ServerName types.String
+	ListenPort types.Int64
+	Root       types.String
+}
+
+// parseServerSettings extracts the typed attributes from the first server
+// block in config. The boolean result is false when there is no server
+// block. Directives missing from the block are returned as null.
+func parseServerSettings(config []*Directive) (serverSettings, bool) {
+	servers := findDirectives(config, "server")
+	if len(servers) == 0 {
+		return serverSettings{}, false
+	}
+	server := servers[0]
+
+	settings := serverSettings{
+		ServerName: types.StringNull(),
+		ListenPort: types.Int64Null(),
+		Root:       types.StringNull(),
+	}
+	if d := server.child("server_name"); d != nil {
+		settings.ServerName = types.StringValue(strings.Join(serverNames(d.Args), " "))
+	}
+	if d := server.child("listen"); d != nil && len(d.Args) > 0 {
+		if port, ok := listenPort(d.Args[0]); ok {
+			settings.ListenPort = types.Int64Value(port)
+		}
+	}
+	if d := server.child("root"); d != nil && len(d.Args) == 1 {
+		settings.Root = types.StringValue(d.Args[0])
+	}
+
+	return settings, true
+}
+
+// listenPort returns the port of a listen address such as "80",
+// "127.0.0.1:8080" or "[::]:443". An address without a port listens on 80;
+// UNIX sockets have no port.
+func listenPort(address string) (int64, bool) {
+	if strings.HasPrefix(address, "unix:") {
+		return 0, false
+	}
+	if port, err := strconv.ParseInt(address, 10, 64); err == nil {
+		return port, true
+	}
+
+	_, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return 80, true
+	}
+	port, err := strconv.ParseInt(portString, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return port, true
+}
+
+// refreshServer parses the remote content of a server block resource. It
+// returns the typed settings found on the host, and warns about every
+// directive that drifted from expected, the configuration rendered from the
+// attributes in state. The boolean result is false when the remote content
+// could not be interpreted.
+func refreshServer(expected []*Directive, remote string) (serverSettings, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	actual, err := parseConfig(remote)
+	if err != nil {
+		diags.AddWarning(
+			"Unparsable Configuration",
+			fmt.Sprintf("The configuration on the host could not be parsed, so the typed attributes were not refreshed: %s", err),
+		)
+		return serverSettings{}, false, diags
+	}
+
+	settings, ok := parseServerSettings(actual)
+	if !ok {
+		diags.AddWarning(
+			"Missing Server Block",
+			"The configuration on the host does not contain a server block, so the typed attributes were not refreshed.",
+		)
+		return serverSettings{}, false, diags
+	}
+
+	if drift := configDrift(expected, actual); len(drift) > 0 {
+		diags.AddWarning(
+			"Configuration Drift Detected",
+			"The configuration on the host no longer matches the one Terraform wrote:\n\n- "+strings.Join(drift, "\n- "),
+		)
+	}
+
+	return settings, true, diags
+}
+
+// serverName returns the refreshed server_name, or current when it lists
+// the same names, so that spacing in the configuration is not reported as a
+// change.
+func (s serverSettings) serverName(current types.String) types.String {
+	if !current.IsNull() && !s.ServerName.IsNull() &&
+		slices.Equal(strings.Fields(current.ValueString()), strings.Fields(s.ServerName.ValueString())) {
+		return current
+	}
+	return s.ServerName
+}
+
+// configDrift describes every difference between two directive trees, one
+// line per directive. Comments and formatting are ignored.
+func configDrift(expected []*Directive, actual []*Directive) []string {
+	var drift []string
+	compareDirectives("", expected, actual, &drift)
+	return drift
+}
+
+func compareDirectives(path string, expected []*Directive, actual []*Directive, drift *[]string) {
+	// Group both sides by key, remembering the order keys first appear in
+	var order []string
+	expectedByKey := make(map[string][]*Directive)
+	actualByKey := make(map[string][]*Directive)
+	for _, side := range []struct {
+		directives []*Directive
+		byKey      map[string][]*Directive
+	}{{expected, expectedByKey}, {actual, actualByKey}} {
+		for _, d := range side.directives {
+			if d.IsComment {
+				continue
+			}
+			key := directiveKey(d)
+			if _, seen := expectedByKey[key]; !seen {
+				if _, seen := actualByKey[key]; !seen {
+					order = append(order, key)
+				}
+			}
+			side.byKey[key] = append(side.byKey[key], d)
+		}
+	}
+
+	for _, key := range order {
+		want, got := expectedByKey[key], actualByKey[key]
+		location := strings.TrimPrefix(path+" > "+key, " > ")
+
+		// Blocks are matched by name and arguments and compared recursively
+		if isBlockKey(want, got) {
+			for i := 0; i < len(want) || i < len(got); i++ {
+				switch {
+				case i >= len(got):
+					*drift = append(*drift, fmt.Sprintf("%s: block removed", location))
+				case i >= len(want):
+					*drift = append(*drift, fmt.Sprintf("%s: block added", location))
+				case want[i].Raw != got[i].Raw:
+					*drift = append(*drift, fmt.Sprintf("%s: block content changed", location))
+				default:
+					compareDirectives(location, want[i].Block, got[i].Block, drift)
+				}
+			}
+			continue
+		}
+
+		// Simple directives are compared as a multiset of argument lists
+		remaining := make(map[string]int)
+		for _, d := range got {
+			remaining[argString(d)]++
+		}
+		var removed []string
+		for _, d := range want {
+			if remaining[argString(d)] > 0 {
+				remaining[argString(d)]--
+				continue
+			}
+			removed = append(removed, argString(d))
+		}
+		var added []string
+		for _, d := range got {
+			if remaining[argString(d)] > 0 {
+				remaining[argString(d)]--
+				added = append(added, argString(d))
+			}
+		}
+
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			switch {
+			case i >= len(added):
+				*drift = append(*drift, fmt.Sprintf("%s: %q removed", location, removed[i]))
+			case i >= len(removed):
+				*drift = append(*drift, fmt.Sprintf("%s: %q added", location, added[i]))
+			default:
+				*drift = append(*drift, fmt.Sprintf("%s: %q changed to %q", location, removed[i], added[i]))
+			}
+		}
+	}
+}
+
+// directiveKey identifies a directive among its siblings. Blocks are keyed
+// by name and arguments, so that each location is compared with its
+// counterpart; simple directives by name only.
+func directiveKey(d *Directive) string {
+	if d.IsBlock && len(d.Args) > 0 {
+		return d.Name + " " + strings.Join(d.Args, " ")
+	}
+	return d.Name
+}
+
+func isBlockKey(want []*Directive, got []*Directive) bool {
+	if len(want) > 0 {
+		return want[0].IsBlock
+	}
+	return len(got) > 0 && got[0].IsBlock
+}
+
+func argString(d *Directive) string {
+	return strings.Join(d.Args, " ")
+}
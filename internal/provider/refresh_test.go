@@ -0,0 +1,80 @@
+package nginx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TestRefreshServerDrift checks that drift is reported against the
+// configuration rendered from state, which is all there is to compare with
+// when the content is not stored.
+func TestRefreshServerDrift(t *testing.T) {
+	block := serverBlock{
+		ListenPort: types.Int64Value(80),
+		ServerName: types.StringValue("example.com"),
+		Root:       types.StringValue("/var/www/html"),
+	}
+	expected := block.directives()
+
+	remote := renderConfig(expected)
+	if _, ok, diags := refreshServer(expected, remote); !ok || diags.WarningsCount() != 0 {
+		t.Fatalf("unchanged configuration: ok = %t, diagnostics = %v", ok, diags)
+	}
+
+	remote = strings.Replace(remote, "/var/www/html", "/srv/www", 1)
+	settings, ok, diags := refreshServer(expected, remote)
+	if !ok {
+		t.Fatalf("changed configuration was not parsed: %v", diags)
+	}
+	if diags.WarningsCount() != 1 || diags[0].Summary() != "Configuration Drift Detected" {
+		t.Errorf("changed configuration: diagnostics = %v, want one drift warning", diags)
+	}
+	if got := settings.Root.ValueString(); got != "/srv/www" {
+		t.Errorf("root = %q, want /srv/www", got)
+	}
+}
+
+func TestServerSettingsServerName(t *testing.T) {
+	tests := []struct {
+		current types.String
+		remote  types.String
+		want    types.String
+	}{
+		{types.StringValue("example.com  www.example.com"), types.StringValue("example.com www.example.com"), types.StringValue("example.com  www.example.com")},
+		{types.StringValue("example.com"), types.StringValue("example.org"), types.StringValue("example.org")},
+		{types.StringNull(), types.StringValue("example.com"), types.StringValue("example.com")},
+		{types.StringValue("example.com"), types.StringNull(), types.StringNull()},
+	}
+
+	for _, tt := range tests {
+		settings := serverSettings{ServerName: tt.remote}
+		if got := settings.serverName(tt.current); !got.Equal(tt.want) {
+			t.Errorf("serverName(%s) with remote %s = %s, want %s", tt.current, tt.remote, got, tt.want)
+		}
+	}
+}
+
+// TestServerNameEmpty checks that the empty server name, spelled "" in the
+// attribute, renders as an empty argument and is refreshed as "" again.
+func TestServerNameEmpty(t *testing.T) {
+	block := serverBlock{
+		ListenPort: types.Int64Null(),
+		ServerName: types.StringValue(`example.com ""`),
+		Root:       types.StringNull(),
+	}
+
+	rendered := renderConfig(block.directives())
+	if !strings.Contains(rendered, "server_name example.com \"\";\n") {
+		t.Fatalf("rendered configuration does not hold the empty server name:\n%s", rendered)
+	}
+
+	settings, ok, diags := refreshServer(block.directives(), rendered)
+	if !ok || diags.WarningsCount() != 0 {
+		t.Fatalf("refreshServer() ok = %t, diagnostics = %v", ok, diags)
+	}
+	if got := settings.ServerName.ValueString(); got != `example.com ""` {
+		t.Errorf("server_name = %q, want %q", got, `example.com ""`)
+	}
+}
@@ -0,0 +1,89 @@
+package nginx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// notFoundMarker is echoed by remote commands when the requested file does
+// not exist, so that a missing file can be told apart from an empty one.
+const notFoundMarker = "NOT_FOUND"
+
+// shellQuote wraps input in single quotes so it is passed to the remote shell
+// as a single literal word.
+func shellQuote(input string) string {
+	return "'" + shellEscape(input) + "'"
+}
+
+// runCommand runs command in a new SSH session and returns its stdout.
+func runCommand(client *ssh.Client, command string) ([]byte, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH session: %w", err)
+	}
+	defer session.Close()
+
+	output, err := session.Output(command)
+	if err != nil {
+		return output, fmt.Errorf("failed to execute command: %w", err)
+	}
+
+	return output, nil
+}
+
+// readRemoteFile returns the content of path on the host. The boolean result
+// is false when the file does not exist.
+func readRemoteFile(client *ssh.Client, path string) (string, bool, error) {
+	quoted := shellQuote(path)
+	command := fmt.Sprintf("if [ -f %s ]; then sudo cat %s; else echo '%s'; fi", quoted, quoted, notFoundMarker)
+
+	output, err := runCommand(client, command)
+	if err != nil {
+		return "", false, err
+	}
+
+	if strings.TrimSpace(string(output)) == notFoundMarker {
+		return "", false, nil
+	}
+
+	return string(output), true, nil
+}
+
+// statRemoteFile returns the checksum, size and modification time of path on
+// the host, computed there with sha256sum and stat. The boolean result is
+// false when the file does not exist.
+func statRemoteFile(client *ssh.Client, path string) (remoteFileInfo, bool, error) {
+	quoted := shellQuote(path)
+	command := fmt.Sprintf("if [ -f %s ]; then sudo cat %s | sha256sum && sudo stat -c '%%s %%Y' %s; else echo '%s'; fi",
+		quoted, quoted, quoted, notFoundMarker)
+
+	output, err := runCommand(client, command)
+	if err != nil {
+		return remoteFileInfo{}, false, err
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) == 1 && fields[0] == notFoundMarker {
+		return remoteFileInfo{}, false, nil
+	}
+
+	// sha256sum prints the checksum and "-" for stdin, stat the size and the
+	// modification time
+	if len(fields) != 4 {
+		return remoteFileInfo{}, false, fmt.Errorf("unexpected output %q", output)
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return remoteFileInfo{}, false, fmt.Errorf("unexpected file size %q", fields[2])
+	}
+	mtime, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return remoteFileInfo{}, false, fmt.Errorf("unexpected modification time %q", fields[3])
+	}
+
+	return remoteFileInfo{SHA256: fields[0], Size: size, Mtime: time.Unix(mtime, 0)}, true, nil
+}
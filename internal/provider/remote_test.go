@@ -0,0 +1,134 @@
+package nginx
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// fakeCommands stand in for the commands the provider runs on the host:
+// sudo runs its arguments as the current user and nginx accepts any
+// configuration.
+var fakeCommands = map[string]string{
+	"sudo":  "#!/bin/sh\nexec \"$@\"\n",
+	"nginx": "#!/bin/sh\nexit 0\n",
+}
+
+// newTestHost starts an SSH server that runs commands with the local shell
+// and returns a client connected to it. Scripts in commands replace or add to
+// fakeCommands.
+func newTestHost(t *testing.T, commands map[string]string) *ssh.Client {
+	t.Helper()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no shell to run commands with")
+	}
+
+	bin := t.TempDir()
+	scripts := make(map[string]string)
+	for name, script := range fakeCommands {
+		scripts[name] = script
+	}
+	for name, script := range commands {
+		scripts[name] = script
+	}
+	for name, script := range scripts {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0o755); err != nil {
+			t.Fatalf("writing fake %s: %s", name, err)
+		}
+	}
+	env := append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating the host key: %s", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("loading the host key: %s", err)
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config, env)
+		}
+	}()
+
+	client, err := ssh.Dial("tcp", listener.Addr().String(), &ssh.ClientConfig{
+		User:            "test",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatalf("connecting to the test host: %s", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// serveTestConn runs the exec requests of the sessions on conn.
+func serveTestConn(conn net.Conn, config *ssh.ServerConfig, env []string) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				if req.Type != "exec" || len(req.Payload) < 4 {
+					req.Reply(false, nil)
+					continue
+				}
+				req.Reply(true, nil)
+
+				cmd := exec.Command("sh", "-c", string(req.Payload[4:]))
+				cmd.Env = env
+				cmd.Stdout = channel
+				cmd.Stderr = channel.Stderr()
+
+				status := uint32(0)
+				if err := cmd.Run(); err != nil {
+					status = 1
+					var exitErr *exec.ExitError
+					if errors.As(err, &exitErr) {
+						status = uint32(exitErr.ExitCode())
+					}
+				}
+				channel.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+				return
+			}
+		}()
+	}
+}
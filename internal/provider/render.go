@@ -0,0 +1,131 @@
+package nginx
+
+import (
+	"strings"
+)
+
+// Directive is a single NGINX directive. Block directives such as server or
+// location carry their children in Block and have IsBlock set, so that an
+// empty block ("events {}") can be told apart from a simple directive.
+//
+// Blocks holding foreign code, such as content_by_lua_block, keep their body
+// verbatim in Raw. Comments read from a file are kept as directives with
+// IsComment set and the text after "#" in Comment.
+type Directive struct {
+	Name    string
+	Args    []string
+	Block   []*Directive
+	IsBlock bool
+	Raw     string
+
+	IsComment bool
+	Comment   string
+
+	// Line is the line the directive starts on when it was parsed.
+	Line int
+}
+
+// simpleDirective builds a directive terminated by a semicolon.
+func simpleDirective(name string, args ...string) *Directive {
+	return &Directive{Name: name, Args: args}
+}
+
+// blockDirective builds a directive with a nested block of children.
+func blockDirective(name string, args []string, children ...*Directive) *Directive {
+	return &Directive{Name: name, Args: args, Block: children, IsBlock: true}
+}
+
+// renderConfig renders a list of directives as NGINX configuration text,
+// indenting nested blocks with tabs and ending with a trailing newline.
+func renderConfig(directives []*Directive) string {
+	var b strings.Builder
+	renderDirectives(&b, directives, 0)
+	return b.String()
+}
+
+func renderDirectives(b *strings.Builder, directives []*Directive, depth int) {
+	for _, d := range directives {
+		b.WriteString(strings.Repeat("\t", depth))
+		if d.IsComment {
+			b.WriteString("#" + d.Comment + "\n")
+			continue
+		}
+
+		b.WriteString(quoteArg(d.Name))
+		for _, arg := range d.Args {
+			b.WriteString(" ")
+			b.WriteString(quoteArg(arg))
+		}
+
+		if !d.IsBlock {
+			b.WriteString(";\n")
+			continue
+		}
+
+		if d.Raw != "" {
+			b.WriteString(" {" + d.Raw + "}\n")
+			continue
+		}
+
+		if len(d.Block) == 0 {
+			b.WriteString(" {}\n")
+			continue
+		}
+
+		b.WriteString(" {\n")
+		renderDirectives(b, d.Block, depth+1)
+		b.WriteString(strings.Repeat("\t", depth))
+		b.WriteString("}\n")
+	}
+}
+
+// quoteArg returns arg in a form NGINX reads back as a single argument.
+// Arguments containing whitespace, semicolons, braces, quotes or a comment
+// sign are wrapped in double quotes; anything else is written as is.
+// Variables are expanded inside double quotes too, so "$host" and
+// "${host}.example.com" keep working once quoted.
+//
+// A dollar sign is always passed through as is. NGINX has no escape for it,
+// so wherever a directive expands variables a $ starts one, quoted or not.
+// A literal dollar sign needs a variable holding it, such as
+// geo $dollar { default "$"; } used as ${dollar}.
+func quoteArg(arg string) string {
+	if arg == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(arg, " \t\r\n;{}\"'#") && !hasEscape(arg) {
+		return arg
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(arg); i++ {
+		switch c := arg[i]; {
+		case c == '"':
+			b.WriteString(`\"`)
+		case c == '\\' && isEscape(arg, i):
+			b.WriteString(`\\`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// isEscape reports whether the backslash at arg[i] would be read by NGINX as
+// an escape sequence. Other backslashes are kept literally, so regular
+// expressions such as \.php$ need no escaping.
+func isEscape(arg string, i int) bool {
+	return i+1 == len(arg) || strings.IndexByte(`"'\\tnr`, arg[i+1]) >= 0
+}
+
+func hasEscape(arg string) bool {
+	for i := 0; i < len(arg); i++ {
+		if arg[i] == '\\' && isEscape(arg, i) {
+			return true
+		}
+	}
+	return false
+}
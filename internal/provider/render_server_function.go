@@ -0,0 +1,131 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &RenderServerFunction{}
+
+// renderServerAttributes are the nginx_site attributes render_server
+// accepts.
+var renderServerAttributes = []string{
+	"listen_port",
+	"server_name",
+	"root",
+	"access",
+	"headers",
+	"security_headers",
+	"locations",
+	"directives",
+	"performance",
+}
+
+func NewRenderServerFunction() function.Function {
+	return &RenderServerFunction{}
+}
+
+// RenderServerFunction defines the function implementation.
+type RenderServerFunction struct{}
+
+// renderServerModel describes the object render_server takes.
+type renderServerModel struct {
+	ListenPort      types.Int64            `tfsdk:"listen_port"`
+	ServerName      types.String           `tfsdk:"server_name"`
+	Root            types.String           `tfsdk:"root"`
+	Access          *AccessModel           `tfsdk:"access"`
+	Headers         map[string]HeaderModel `tfsdk:"headers"`
+	SecurityHeaders *SecurityHeadersModel  `tfsdk:"security_headers"`
+	Locations       []LocationModel        `tfsdk:"locations"`
+	Directives      []ExtraDirectiveModel  `tfsdk:"directives"`
+	Performance     *PerformanceModel      `tfsdk:"performance"`
+}
+
+func (f *RenderServerFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "render_server"
+}
+
+func (f *RenderServerFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Renders a server block",
+		MarkdownDescription: "Renders the server block `nginx_site` writes for the given settings, without " +
+			"connecting to a host. Features that depend on the host, such as brotli compression, are left out.",
+
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name: "server",
+				MarkdownDescription: "An object with any of the `nginx_site` attributes `listen_port`, `server_name`, " +
+					"`root`, `access`, `headers`, `security_headers`, `locations`, `directives` and `performance`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RenderServerFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var server types.Dynamic
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &server))
+	if resp.Error != nil {
+		return
+	}
+
+	block, err := decodeRenderServer(ctx, server)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, renderConfig(block.directives())))
+}
+
+// decodeRenderServer converts the object passed to render_server into a
+// server block, using the nginx_site schema for its attribute types.
+func decodeRenderServer(ctx context.Context, server types.Dynamic) (serverBlock, error) {
+	var site resource.SchemaResponse
+	NewSiteResource().Schema(ctx, resource.SchemaRequest{}, &site)
+
+	attributeTypes := make(map[string]attr.Type, len(renderServerAttributes))
+	for _, name := range renderServerAttributes {
+		attributeTypes[name] = site.Schema.Attributes[name].GetType()
+	}
+	objectType := types.ObjectType{AttrTypes: attributeTypes}
+
+	value, err := server.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		return serverBlock{}, err
+	}
+	conformed, err := conformValue(value, objectType.TerraformType(ctx), "server")
+	if err != nil {
+		return serverBlock{}, err
+	}
+
+	object, err := objectType.ValueFromTerraform(ctx, conformed)
+	if err != nil {
+		return serverBlock{}, fmt.Errorf("invalid server: %w", err)
+	}
+
+	var model renderServerModel
+	if diags := object.(types.Object).As(ctx, &model, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return serverBlock{}, fmt.Errorf("%s", diags.Errors()[0].Detail())
+	}
+
+	return serverBlock{
+		ListenPort:  model.ListenPort,
+		ServerName:  model.ServerName,
+		Root:        model.Root,
+		Access:      model.Access,
+		Headers:     model.Headers,
+		Security:    model.SecurityHeaders,
+		Locations:   model.Locations,
+		Directives:  model.Directives,
+		Performance: model.Performance,
+	}, nil
+}
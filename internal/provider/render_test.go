@@ -0,0 +1,56 @@
+package nginx
+
+import (
+	"testing"
+)
+
+func TestQuoteArg(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"", `""`},
+		{"example.com", "example.com"},
+		{"$host", "$host"},
+		{"${host}.example.com", `"${host}.example.com"`},
+		{"$scheme://$host$request_uri", "$scheme://$host$request_uri"},
+		{`\.php$`, `\.php$`},
+		{"max-age=31536000; includeSubDomains", `"max-age=31536000; includeSubDomains"`},
+		{"Basic realm $host", `"Basic realm $host"`},
+		{"a{b}", `"a{b}"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"it's", `"it's"`},
+		{"#fragment", `"#fragment"`},
+		{"tab\there", "\"tab\there\""},
+		{`c:\`, `"c:\\"`},
+		{`a\"b`, `"a\\\"b"`},
+		{`a\nb`, `"a\\nb"`},
+	}
+
+	for _, tt := range tests {
+		if got := quoteArg(tt.arg); got != tt.want {
+			t.Errorf("quoteArg(%q) = %s, want %s", tt.arg, got, tt.want)
+		}
+	}
+}
+
+// TestQuoteArgRoundTrip checks that the parser reads every quoted argument
+// back as the original string, dollar signs included.
+func TestQuoteArgRoundTrip(t *testing.T) {
+	args := []string{
+		"", "plain", "$host", "${host}.example.com", `~*\.(css|js)$`, "a b", "x;y", `say "hi"`,
+		"it's", "#x", `c:\`, `a\"b`, `a\nb`, "{}", "$1$2",
+	}
+
+	for _, arg := range args {
+		config := renderConfig([]*Directive{simpleDirective("set", "$v", arg)})
+		directives, err := parseConfig(config)
+		if err != nil {
+			t.Errorf("parsing %q: %s", config, err)
+			continue
+		}
+		if got := directives[0].Args; len(got) != 2 || got[1] != arg {
+			t.Errorf("%q rendered as %q parses back as %q", arg, config, got)
+		}
+	}
+}
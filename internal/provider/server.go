@@ -0,0 +1,168 @@
+package nginx
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// LocationModel describes a location block inside a server block.
+type LocationModel struct {
+	Path       types.String           `tfsdk:"path"`
+	Modifier   types.String           `tfsdk:"modifier"`
+	TryFiles   []types.String         `tfsdk:"try_files"`
+	Access     *AccessModel           `tfsdk:"access"`
+	Headers    map[string]HeaderModel `tfsdk:"headers"`
+	Directives []ExtraDirectiveModel  `tfsdk:"directives"`
+}
+
+func locationsAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		MarkdownDescription: "Location blocks rendered inside the server block. " +
+			"When omitted, a single `location /` serving static files is rendered.",
+		Optional: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"path": schema.StringAttribute{
+					MarkdownDescription: "The URI or regular expression the location matches.",
+					Required:            true,
+				},
+				"modifier": schema.StringAttribute{
+					MarkdownDescription: "An optional match modifier: `=`, `~`, `~*` or `^~`.",
+					Optional:            true,
+					Validators: []validator.String{
+						stringvalidator.OneOf("=", "~", "~*", "^~"),
+					},
+				},
+				"try_files": schema.ListAttribute{
+					MarkdownDescription: "Arguments of the `try_files` directive.",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+				"access":     accessAttribute("location"),
+				"headers":    headersAttribute("location"),
+				"directives": extraDirectivesAttribute("at the end of the location block"),
+			},
+		},
+	}
+}
+
+// serverBlock holds the structured settings that the site, API, proxy and
+// config resources render into a single NGINX server block.
+type serverBlock struct {
+	ListenPort types.Int64
+	ServerName types.String
+	Root       types.String
+	Access     *AccessModel
+	Headers    map[string]HeaderModel
+	Security   *SecurityHeadersModel
+	Locations  []LocationModel
+	Directives []ExtraDirectiveModel
+
+	Performance *PerformanceModel
+	// Brotli is set when the brotli module is available on the host.
+	Brotli bool
+}
+
+// empty reports whether none of the structured attributes are set, as for
+// resources that write the content set in the configuration verbatim.
+func (s serverBlock) empty() bool {
+	return s.ListenPort.IsNull() && s.ServerName.IsNull() && s.Root.IsNull() && s.Access == nil &&
+		len(s.Headers) == 0 && s.Security == nil && len(s.Locations) == 0 && len(s.Directives) == 0 &&
+		s.Performance == nil
+}
+
+// directives builds the server block. Without explicit locations it keeps
+// the static-file layout the resources have always rendered. Unset listen,
+// server_name and root directives are left to the NGINX defaults.
+func (s serverBlock) directives() []*Directive {
+	server := blockDirective("server", nil)
+	if !s.ListenPort.IsNull() {
+		server.Block = append(server.Block, simpleDirective("listen", strconv.FormatInt(s.ListenPort.ValueInt64(), 10)))
+	}
+	if !s.ServerName.IsNull() {
+		server.Block = append(server.Block, simpleDirective("server_name", serverNameArgs(strings.Fields(s.ServerName.ValueString()))...))
+	}
+	if !s.Root.IsNull() {
+		server.Block = append(server.Block, simpleDirective("root", s.Root.ValueString()))
+	}
+	server.Block = append(server.Block, simpleDirective("index", "index.html"))
+	server.Block = append(server.Block, s.Performance.directives(s.Brotli)...)
+	server.Block = append(server.Block, accessDirectives(s.Access)...)
+
+	headers := mergeHeaders(presetHeaders(s.Security), mapHeaders(s.Headers))
+	server.Block = append(server.Block, headerDirectives(headers)...)
+
+	if len(s.Locations) == 0 {
+		server.Block = append(server.Block, blockDirective("location", []string{"/"},
+			simpleDirective("try_files", "$uri", "$uri/", "=404"),
+		))
+	}
+	for _, location := range s.Locations {
+		server.Block = append(server.Block, location.directive(headers))
+	}
+	server.Block = append(server.Block, s.Performance.cacheLocations(headers)...)
+	server.Block = append(server.Block, extraDirectives(s.Directives)...)
+
+	return []*Directive{server}
+}
+
+// emptyServerName is how server names are spelled in attributes for the
+// empty name, which matches requests without a Host header.
+const emptyServerName = `""`
+
+// serverNameArgs returns the arguments of a server_name directive for
+// names. The empty name is passed as an empty argument, which renders as "",
+// rather than as two literal quote characters.
+func serverNameArgs(names []string) []string {
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == emptyServerName {
+			name = ""
+		}
+		args = append(args, name)
+	}
+	return args
+}
+
+// serverNames returns the server names of a server_name directive as they
+// are spelled in attributes, the reverse of serverNameArgs.
+func serverNames(args []string) []string {
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "" {
+			arg = emptyServerName
+		}
+		names = append(names, arg)
+	}
+	return names
+}
+
+// directive builds the location block. NGINX discards every inherited
+// add_header as soon as a location sets one of its own, so the server-level
+// headers are repeated into locations that define headers.
+func (l LocationModel) directive(inherited []header) *Directive {
+	var args []string
+	if !l.Modifier.IsNull() {
+		args = append(args, l.Modifier.ValueString())
+	}
+	args = append(args, l.Path.ValueString())
+
+	location := blockDirective("location", args)
+	if len(l.TryFiles) > 0 {
+		location.Block = append(location.Block, simpleDirective("try_files", stringValues(l.TryFiles)...))
+	}
+	location.Block = append(location.Block, accessDirectives(l.Access)...)
+
+	if len(l.Headers) > 0 || definesDirective(l.Directives, "add_header") {
+		headers := mergeHeaders(inherited, mapHeaders(l.Headers))
+		location.Block = append(location.Block, headerDirectives(headers)...)
+	}
+	location.Block = append(location.Block, extraDirectives(l.Directives)...)
+
+	return location
+}
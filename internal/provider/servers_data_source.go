@@ -0,0 +1,658 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ServersDataSource{}
+
+// configDumpHeader prefixes the line nginx -T prints before every file.
+const configDumpHeader = "# configuration file "
+
+// resourceNameAttributes maps the resource types the generated configuration
+// can target onto the attribute holding their name.
+var resourceNameAttributes = map[string]string{
+	"nginx_site":   "site_name",
+	"nginx_api":    "api_name",
+	"nginx_proxy":  "proxy_name",
+	"nginx_config": "config_name",
+}
+
+// invalidNameChars matches the characters not allowed in a Terraform
+// resource name.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+func NewServersDataSource() datasource.DataSource {
+	return &ServersDataSource{}
+}
+
+// ServersDataSource defines the data source implementation.
+type ServersDataSource struct {
+	client interface{} // Use interface{} to accept SSH client passed from provider.go
+}
+
+// ServersDataSourceModel describes the data source data model.
+type ServersDataSourceModel struct {
+	GenerateConfig types.String            `tfsdk:"generate_config"`
+	ConfigFile     types.String            `tfsdk:"config_file"`
+	Servers        []DiscoveredServerModel `tfsdk:"servers"`
+	ImportConfig   types.String            `tfsdk:"import_config"`
+	ResourceConfig types.String            `tfsdk:"resource_config"`
+	Id             types.String            `tfsdk:"id"`
+}
+
+// DiscoveredServerModel describes a server block found on the host.
+type DiscoveredServerModel struct {
+	File         types.String   `tfsdk:"file"`
+	Line         types.Int64    `tfsdk:"line"`
+	ServerNames  []types.String `tfsdk:"server_names"`
+	Listens      []types.String `tfsdk:"listens"`
+	IncludeChain []types.String `tfsdk:"include_chain"`
+	Importable   types.Bool     `tfsdk:"importable"`
+}
+
+// discoveredServer is a server block found while walking the configuration.
+type discoveredServer struct {
+	file   string
+	chain  []string
+	server *Directive
+	// alone is set when the server block is the only directive in its file.
+	alone bool
+}
+
+func (d *ServersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_servers"
+}
+
+func (d *ServersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists every HTTP server block reachable from the main NGINX configuration, " +
+			"as reported by `nginx -T`, and optionally generates the configuration to bring them under management.",
+
+		Attributes: map[string]schema.Attribute{
+			"generate_config": schema.StringAttribute{
+				MarkdownDescription: "The resource type to generate `import_config` and `resource_config` for: " +
+					"`nginx_site`, `nginx_api`, `nginx_proxy` or `nginx_config`. Nothing is generated when unset.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("nginx_site", "nginx_api", "nginx_proxy", "nginx_config"),
+				},
+			},
+			"config_file": schema.StringAttribute{
+				MarkdownDescription: "The main configuration file the discovery started from.",
+				Computed:            true,
+			},
+			"servers": schema.ListNestedAttribute{
+				MarkdownDescription: "The server blocks, in the order NGINX reads them.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"file": schema.StringAttribute{
+							MarkdownDescription: "The file holding the server block.",
+							Computed:            true,
+						},
+						"line": schema.Int64Attribute{
+							MarkdownDescription: "The line the server block starts on.",
+							Computed:            true,
+						},
+						"server_names": schema.ListAttribute{
+							MarkdownDescription: "The names from the `server_name` directives.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"listens": schema.ListAttribute{
+							MarkdownDescription: "The arguments of each `listen` directive, such as `443 ssl`.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"include_chain": schema.ListAttribute{
+							MarkdownDescription: "The files included on the way to `file`, starting with the main configuration file.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"importable": schema.BoolAttribute{
+							MarkdownDescription: "Whether the file holds this server block and nothing but comments besides, " +
+								"so that it can be imported into a resource without losing other configuration.",
+							Computed: true,
+						},
+					},
+				},
+			},
+			"import_config": schema.StringAttribute{
+				MarkdownDescription: "`import` blocks for every importable server block, ready for " +
+					"`terraform plan -generate-config-out`.",
+				Computed: true,
+			},
+			"resource_config": schema.StringAttribute{
+				MarkdownDescription: "`import` blocks together with matching resource blocks for every importable server block. " +
+					"The server block is described with the structured attributes, `directives` and `locations` when " +
+					"they render it unchanged, and written verbatim through `content` otherwise.",
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The main configuration file.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ServersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ssh.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ServersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ServersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Dump the full configuration, as NGINX itself resolves it
+	output, err := runCommand(d.client.(*ssh.Client), "sudo nginx -T 2>/dev/null")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to dump the NGINX configuration, check it with 'nginx -t': %s", err),
+		)
+		return
+	}
+
+	order, files := splitConfigDump(string(output))
+	if len(order) == 0 {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			"The output of 'nginx -T' does not contain any configuration file.",
+		)
+		return
+	}
+
+	discovered, diags := discoverServers(order, files)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ConfigFile = types.StringValue(order[0])
+	data.Id = types.StringValue(order[0])
+	data.Servers = make([]DiscoveredServerModel, 0, len(discovered))
+	for _, server := range discovered {
+		data.Servers = append(data.Servers, server.model())
+	}
+
+	data.ImportConfig = types.StringNull()
+	data.ResourceConfig = types.StringNull()
+	if !data.GenerateConfig.IsNull() {
+		imports, resources := generateServerConfig(data.GenerateConfig.ValueString(), discovered, files)
+		data.ImportConfig = types.StringValue(imports)
+		data.ResourceConfig = types.StringValue(resources)
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Discovered %d server blocks", len(discovered)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// splitConfigDump splits the output of nginx -T into the files it holds. It
+// returns the file names in the order NGINX read them, the main
+// configuration file first.
+func splitConfigDump(dump string) ([]string, map[string]string) {
+	var order []string
+	files := make(map[string]string)
+
+	var current string
+	var content strings.Builder
+	flush := func() {
+		if current != "" {
+			files[current] = content.String()
+		}
+		content.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(dump, "\n") {
+		header := strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(header, configDumpHeader) && strings.HasSuffix(header, ":") {
+			flush()
+			current = strings.TrimSuffix(strings.TrimPrefix(header, configDumpHeader), ":")
+			if _, seen := files[current]; !seen {
+				order = append(order, current)
+			}
+			continue
+		}
+		content.WriteString(line)
+	}
+	flush()
+
+	return order, files
+}
+
+// discoverServers walks the configuration from the main file through its
+// includes and returns the HTTP server blocks in the order NGINX reads them.
+func discoverServers(order []string, files map[string]string) ([]discoveredServer, diag.Diagnostics) {
+	w := &serverWalker{
+		order:  order,
+		files:  files,
+		prefix: filepath.Dir(order[0]),
+		parsed: make(map[string][]*Directive),
+	}
+	w.walkFile(order[0], nil, false)
+	return w.servers, w.diags
+}
+
+type serverWalker struct {
+	order   []string
+	files   map[string]string
+	prefix  string
+	parsed  map[string][]*Directive
+	servers []discoveredServer
+	diags   diag.Diagnostics
+}
+
+func (w *serverWalker) walkFile(file string, chain []string, inStream bool) {
+	// Guard against include loops
+	for _, parent := range chain {
+		if parent == file {
+			return
+		}
+	}
+
+	config, ok := w.parsed[file]
+	if !ok {
+		var err error
+		config, err = parseConfig(w.files[file])
+		if err != nil {
+			w.diags.AddWarning(
+				"Unparsable Configuration",
+				fmt.Sprintf("Skipping %s, which could not be parsed: %s", file, err),
+			)
+		}
+		w.parsed[file] = config
+	}
+
+	w.walkDirectives(file, append(append([]string(nil), chain...), file), config, inStream, true)
+}
+
+func (w *serverWalker) walkDirectives(file string, chain []string, directives []*Directive, inStream bool, topLevel bool) {
+	// Count the directives of substance, to tell whether a server block
+	// stands alone in its file
+	count := 0
+	for _, d := range directives {
+		if !d.IsComment {
+			count++
+		}
+	}
+
+	for _, d := range directives {
+		switch {
+		case d.IsComment:
+			continue
+		case d.Name == "include" && len(d.Args) == 1:
+			for _, included := range w.resolveInclude(d.Args[0]) {
+				w.walkFile(included, chain, inStream)
+			}
+		case d.Name == "server" && d.IsBlock && !inStream:
+			w.servers = append(w.servers, discoveredServer{
+				file:   file,
+				chain:  chain,
+				server: d,
+				alone:  topLevel && count == 1,
+			})
+		case d.IsBlock:
+			w.walkDirectives(file, chain, d.Block, inStream || d.Name == "stream", false)
+		}
+	}
+}
+
+// resolveInclude returns the dumped files an include pattern matches, in the
+// order NGINX read them. Relative patterns are resolved against the
+// directory of the main configuration file.
+func (w *serverWalker) resolveInclude(pattern string) []string {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(w.prefix, pattern)
+	}
+
+	var matches []string
+	for _, file := range w.order {
+		if ok, err := filepath.Match(pattern, file); err == nil && ok {
+			matches = append(matches, file)
+		}
+	}
+	return matches
+}
+
+// model describes the server block.
+func (s discoveredServer) model() DiscoveredServerModel {
+	model := DiscoveredServerModel{
+		File:         types.StringValue(s.file),
+		Line:         types.Int64Value(int64(s.server.Line)),
+		ServerNames:  []types.String{},
+		Listens:      []types.String{},
+		IncludeChain: []types.String{},
+		Importable:   types.BoolValue(s.importable()),
+	}
+	for _, d := range s.server.Block {
+		switch {
+		case d.IsComment:
+			continue
+		case d.Name == "server_name":
+			for _, name := range serverNames(d.Args) {
+				model.ServerNames = append(model.ServerNames, types.StringValue(name))
+			}
+		case d.Name == "listen":
+			model.Listens = append(model.Listens, types.StringValue(strings.Join(d.Args, " ")))
+		}
+	}
+	for _, file := range s.chain[:len(s.chain)-1] {
+		model.IncludeChain = append(model.IncludeChain, types.StringValue(file))
+	}
+	return model
+}
+
+// importable reports whether the server block can be imported without
+// losing configuration: it has to be the only directive of substance in its
+// file.
+func (s discoveredServer) importable() bool {
+	return s.alone
+}
+
+// generateServerConfig renders import blocks, and import blocks together
+// with resource blocks, for every importable server. files holds the content
+// of every configuration file.
+func generateServerConfig(resourceType string, servers []discoveredServer, files map[string]string) (string, string) {
+	var imports, resources strings.Builder
+	nameAttribute := resourceNameAttributes[resourceType]
+	used := make(map[string]bool)
+
+	for _, server := range servers {
+		if !server.importable() {
+			continue
+		}
+
+		name := uniqueResourceName(server, used)
+		importBlock := fmt.Sprintf("import {\n  to = %s.%s\n  id = %s\n}\n\n",
+			resourceType, name, hclString(name+":"+server.file))
+		imports.WriteString(importBlock)
+		resources.WriteString(importBlock)
+
+		attributes := [][2]string{
+			{nameAttribute, hclString(name)},
+			{"path", hclString(server.file)},
+		}
+		if structured, ok := server.attributes(resourceType == "nginx_site"); ok {
+			attributes = append(attributes, structured...)
+		} else {
+			attributes = append(attributes, [2]string{"content", hclHeredoc(files[server.file])})
+		}
+
+		width := 0
+		for _, attribute := range attributes {
+			width = max(width, len(attribute[0]))
+		}
+		fmt.Fprintf(&resources, "resource %q %q {\n", resourceType, name)
+		for _, attribute := range attributes {
+			fmt.Fprintf(&resources, "  %-*s = %s\n", width, attribute[0], attribute[1])
+		}
+		resources.WriteString("}\n\n")
+	}
+
+	return strings.TrimSuffix(imports.String(), "\n"), strings.TrimSuffix(resources.String(), "\n")
+}
+
+// attributes describes the server block with the attributes an import
+// reconstructs, rendered as HCL. The boolean result is false when those
+// attributes would not render the server block unchanged, as the resources
+// always render some directives of their own.
+func (s discoveredServer) attributes(structuredLocations bool) ([][2]string, bool) {
+	imported := importServerBlock(s.server, structuredLocations)
+	if drift := configDrift([]*Directive{s.server}, imported.serverBlock().directives()); len(drift) > 0 {
+		return nil, false
+	}
+
+	var attributes [][2]string
+	if !imported.Settings.ServerName.IsNull() {
+		attributes = append(attributes, [2]string{"server_name", hclString(imported.Settings.ServerName.ValueString())})
+	}
+	if !imported.Settings.ListenPort.IsNull() {
+		attributes = append(attributes, [2]string{"listen_port", strconv.FormatInt(imported.Settings.ListenPort.ValueInt64(), 10)})
+	}
+	if !imported.Settings.Root.IsNull() {
+		attributes = append(attributes, [2]string{"root", hclString(imported.Settings.Root.ValueString())})
+	}
+	if imported.Access != nil {
+		attributes = append(attributes, [2]string{"access", hclAccess(imported.Access, "  ")})
+	}
+	if len(imported.Headers) > 0 {
+		attributes = append(attributes, [2]string{"headers", hclHeaders(imported.Headers, "  ")})
+	}
+	if len(imported.Locations) > 0 {
+		var locations []string
+		for _, location := range imported.Locations {
+			locations = append(locations, hclLocation(location, "    "))
+		}
+		attributes = append(attributes, [2]string{"locations", "[\n" + strings.Join(locations, ",\n") + ",\n  ]"})
+	}
+	if len(imported.Directives) > 0 {
+		attributes = append(attributes, [2]string{"directives", hclDirectives(imported.Directives, "  ")})
+	}
+	return attributes, true
+}
+
+// hclObject renders attributes as an HCL object, aligned the way terraform
+// fmt does, indented to follow indent.
+func hclObject(attributes [][2]string, indent string) string {
+	width := 0
+	for _, attribute := range attributes {
+		width = max(width, len(attribute[0]))
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, attribute := range attributes {
+		fmt.Fprintf(&b, "%s  %-*s = %s\n", indent, width, attribute[0], attribute[1])
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func hclLocation(location LocationModel, indent string) string {
+	attributes := [][2]string{{"path", hclString(location.Path.ValueString())}}
+	if !location.Modifier.IsNull() {
+		attributes = append(attributes, [2]string{"modifier", hclString(location.Modifier.ValueString())})
+	}
+	if len(location.TryFiles) > 0 {
+		attributes = append(attributes, [2]string{"try_files", hclList(location.TryFiles)})
+	}
+	if location.Access != nil {
+		attributes = append(attributes, [2]string{"access", hclAccess(location.Access, indent+"  ")})
+	}
+	if len(location.Headers) > 0 {
+		attributes = append(attributes, [2]string{"headers", hclHeaders(location.Headers, indent+"  ")})
+	}
+	if len(location.Directives) > 0 {
+		attributes = append(attributes, [2]string{"directives", hclDirectives(location.Directives, indent+"  ")})
+	}
+	return indent + hclObject(attributes, indent)
+}
+
+func hclAccess(access *AccessModel, indent string) string {
+	var attributes [][2]string
+	if !access.Satisfy.IsNull() {
+		attributes = append(attributes, [2]string{"satisfy", hclString(access.Satisfy.ValueString())})
+	}
+
+	var rules strings.Builder
+	rules.WriteString("[\n")
+	for _, rule := range access.Rules {
+		fmt.Fprintf(&rules, "%s    { action = %s, source = %s },\n", indent,
+			hclString(rule.Action.ValueString()), hclString(rule.Source.ValueString()))
+	}
+	rules.WriteString(indent + "  ]")
+	attributes = append(attributes, [2]string{"rules", rules.String()})
+
+	return hclObject(attributes, indent)
+}
+
+func hclHeaders(headers map[string]HeaderModel, indent string) string {
+	var attributes [][2]string
+	for _, h := range mapHeaders(headers) {
+		value := "{ value = " + hclString(h.value)
+		if h.always {
+			value += ", always = true"
+		}
+		attributes = append(attributes, [2]string{hclString(h.name), value + " }"})
+	}
+	return hclObject(attributes, indent)
+}
+
+// hclDirectives renders extra directives as an HCL list, one object per
+// line, indented to follow indent.
+func hclDirectives(directives []ExtraDirectiveModel, indent string) string {
+	var b strings.Builder
+	b.WriteString("[\n")
+	for _, d := range directives {
+		fmt.Fprintf(&b, "%s  { name = %s", indent, hclString(d.Name.ValueString()))
+		if len(d.Args) > 0 {
+			b.WriteString(", args = " + hclList(d.Args))
+		}
+		if d.Block != nil {
+			b.WriteString(", block = [")
+			for i, child := range d.Block {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				fmt.Fprintf(&b, " { name = %s", hclString(child.Name.ValueString()))
+				if len(child.Args) > 0 {
+					b.WriteString(", args = " + hclList(child.Args))
+				}
+				b.WriteString(" }")
+			}
+			b.WriteString(" ]")
+		}
+		b.WriteString(" },\n")
+	}
+	b.WriteString(indent + "]")
+	return b.String()
+}
+
+func hclList(values []types.String) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, hclString(value.ValueString()))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// uniqueResourceName derives a Terraform resource name from the first
+// server name, or from the file name for catch-all servers.
+func uniqueResourceName(server discoveredServer, used map[string]bool) string {
+	base := ""
+	if d := server.server.child("server_name"); d != nil {
+		for _, name := range d.Args {
+			if name != "_" && name != "" {
+				base = name
+				break
+			}
+		}
+	}
+	if base == "" {
+		base = filepath.Base(server.file)
+	}
+
+	base = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(base), "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "server_" + base
+	}
+
+	name := base
+	for i := 2; used[name]; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	used[name] = true
+	return name
+}
+
+// hclString quotes s as an HCL string literal, escaping template sequences.
+// HCL knows fewer escapes than Go, so other non-printable characters are
+// written as \u or \U sequences.
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '"':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsPrint(r):
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			fmt.Fprintf(&b, `\U%08X`, r)
+		default:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		}
+	}
+	b.WriteByte('"')
+
+	quoted := strings.ReplaceAll(b.String(), "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+// hclHeredoc renders s as an HCL heredoc, escaping template sequences. A
+// string without a final newline, which a heredoc would add, is quoted
+// instead.
+func hclHeredoc(s string) string {
+	if !strings.HasSuffix(s, "\n") {
+		return hclString(s)
+	}
+
+	// The closing marker may be indented, so no trimmed line can match it
+	delimiter := "EOT"
+	lines := strings.Split(s, "\n")
+	for slices.ContainsFunc(lines, func(line string) bool { return strings.TrimSpace(line) == delimiter }) {
+		delimiter += "_"
+	}
+	s = strings.ReplaceAll(s, "${", "$${")
+	s = strings.ReplaceAll(s, "%{", "%%{")
+	return "<<" + delimiter + "\n" + s + delimiter
+}
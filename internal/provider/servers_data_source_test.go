@@ -0,0 +1,147 @@
+package nginx
+
+import (
+	"strings"
+	"testing"
+)
+
+const testConfigDump = `# configuration file /etc/nginx/nginx.conf:
+http {
+    include /etc/nginx/sites-enabled/*;
+}
+
+# configuration file /etc/nginx/sites-enabled/example:
+# Managed by hand
+server {
+    listen 80;
+    server_name example.com www.example.com;
+    root /var/www/example;
+    index index.html;
+    add_header X-Frame-Options DENY always;
+    deny 10.0.0.1;
+    location / {
+        try_files $uri $uri/ =404;
+    }
+    location ~ \.php$ {
+        fastcgi_pass unix:/run/php/php-fpm.sock;
+    }
+}
+
+# configuration file /etc/nginx/sites-enabled/mixed:
+upstream backend {
+    server 127.0.0.1:8080;
+}
+server {
+    listen 80;
+    server_name mixed.example.com;
+}
+
+# configuration file /etc/nginx/sites-enabled/nested:
+server {
+    listen 443 ssl;
+    server_name nested.example.com;
+    location / {
+        if ($request_method = POST) {
+            return 405;
+        }
+    }
+}
+`
+
+func discoverTestServers(t *testing.T) []discoveredServer {
+	t.Helper()
+
+	order, files := splitConfigDump(testConfigDump)
+	servers, diags := discoverServers(order, files)
+	if diags.HasError() || len(servers) != 3 {
+		t.Fatalf("discoverServers() = %d servers, diagnostics %v, want 3 servers", len(servers), diags)
+	}
+	return servers
+}
+
+func TestDiscoveredServerImportable(t *testing.T) {
+	want := map[string]bool{
+		"/etc/nginx/sites-enabled/example": true,
+		"/etc/nginx/sites-enabled/mixed":   false,
+		"/etc/nginx/sites-enabled/nested":  true,
+	}
+	for _, server := range discoverTestServers(t) {
+		if got := server.importable(); got != want[server.file] {
+			t.Errorf("importable() for %s = %t, want %t", server.file, got, want[server.file])
+		}
+	}
+}
+
+func TestGenerateServerConfig(t *testing.T) {
+	_, files := splitConfigDump(testConfigDump)
+	servers := discoverTestServers(t)
+
+	_, resources := generateServerConfig("nginx_site", servers, files)
+	for _, want := range []string{
+		`server_name = "example.com www.example.com"`,
+		`listen_port = 80`,
+		`root        = "/var/www/example"`,
+		"access      = {\n    rules = [\n      { action = \"deny\", source = \"10.0.0.1\" },\n    ]\n  }",
+		"headers     = {\n    \"X-Frame-Options\" = { value = \"DENY\", always = true }\n  }",
+		"      path      = \"/\"\n      try_files = [\"$uri\", \"$uri/\", \"=404\"]\n",
+		"      modifier   = \"~\"\n",
+		`{ name = "fastcgi_pass", args = ["unix:/run/php/php-fpm.sock"] },`,
+		// Nested blocks cannot be described by the structured attributes
+		"content   = <<EOT\nserver {\n    listen 443 ssl;",
+		"    if ($request_method = POST) {",
+	} {
+		if !strings.Contains(resources, want) {
+			t.Errorf("nginx_site resource_config does not contain %q:\n%s", want, resources)
+		}
+	}
+	if strings.Contains(resources, "mixed") {
+		t.Errorf("nginx_site resource_config contains the server that shares its file:\n%s", resources)
+	}
+
+	// Without locations, the default location is left to the resource and
+	// the others become extra directives
+	_, resources = generateServerConfig("nginx_api", servers[:1], files)
+	want := `{ name = "location", args = ["~", "\\.php$"], block = [ { name = "fastcgi_pass", args = ["unix:/run/php/php-fpm.sock"] } ] },`
+	if !strings.Contains(resources, want) || strings.Contains(resources, "try_files") {
+		t.Errorf("nginx_api resource_config does not contain %q alone:\n%s", want, resources)
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"example.com", `"example.com"`},
+		{`say "hi" \ bye`, `"say \"hi\" \\ bye"`},
+		{"a\tb\r\n", `"a\tb\r\n"`},
+		{"bell\a tab\v nul\x00", `"bell\u0007 tab\u000B nul\u0000"`},
+		{"zero\u200bwidth", `"zero\u200Bwidth"`},
+		{"café", `"café"`},
+		{"${host} %{if}", `"$${host} %%{if}"`},
+	}
+
+	for _, tt := range tests {
+		if got := hclString(tt.s); got != tt.want {
+			t.Errorf("hclString(%q) = %s, want %s", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestHCLHeredoc(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"a;\n", "<<EOT\na;\nEOT"},
+		{"set $x \"${host}\";\n", "<<EOT\nset $x \"$${host}\";\nEOT"},
+		{"  EOT\n", "<<EOT_\n  EOT\nEOT_"},
+		{"a;", `"a;"`},
+	}
+
+	for _, tt := range tests {
+		if got := hclHeredoc(tt.s); got != tt.want {
+			t.Errorf("hclHeredoc(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
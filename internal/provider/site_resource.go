@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/crypto/ssh"
@@ -18,6 +22,10 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &SiteResource{}
 var _ resource.ResourceWithImportState = &SiteResource{}
+var _ resource.ResourceWithModifyPlan = &SiteResource{}
+var _ resource.ResourceWithValidateConfig = &SiteResource{}
+var _ resource.ResourceWithConfigValidators = &SiteResource{}
+var _ resource.ResourceWithUpgradeState = &SiteResource{}
 
 func NewSiteResource() resource.Resource {
 	return &SiteResource{}
@@ -25,18 +33,45 @@ func NewSiteResource() resource.Resource {
 
 // SiteResource defines the resource implementation.
 type SiteResource struct {
-	client interface{} // Use interface{} to accept SSH client passed from provider.go
+	client            interface{} // Use interface{} to accept SSH client passed from provider.go
+	configDirectories []string
 }
 
 // SiteResourceModel describes the resource data model.
 type SiteResourceModel struct {
-	ServerName types.String `tfsdk:"server_name"`
-	ListenPort types.Int64  `tfsdk:"listen_port"`
-	Root       types.String `tfsdk:"root"`
-	Path       types.String `tfsdk:"path"`
-	Content    types.String `tfsdk:"content"`
-	Id         types.String `tfsdk:"id"`
-	SiteName   types.String `tfsdk:"site_name"`
+	ServerName      types.String           `tfsdk:"server_name"`
+	ListenPort      types.Int64            `tfsdk:"listen_port"`
+	Root            types.String           `tfsdk:"root"`
+	Path            types.String           `tfsdk:"path"`
+	Content         types.String           `tfsdk:"content"`
+	StoreContent    types.Bool             `tfsdk:"store_content"`
+	ContentSHA256   types.String           `tfsdk:"content_sha256"`
+	Size            types.Int64            `tfsdk:"size"`
+	Mtime           types.String           `tfsdk:"mtime"`
+	Id              types.String           `tfsdk:"id"`
+	SiteName        types.String           `tfsdk:"site_name"`
+	Access          *AccessModel           `tfsdk:"access"`
+	Headers         map[string]HeaderModel `tfsdk:"headers"`
+	SecurityHeaders *SecurityHeadersModel  `tfsdk:"security_headers"`
+	Directives      []ExtraDirectiveModel  `tfsdk:"directives"`
+	Locations       []LocationModel        `tfsdk:"locations"`
+	Performance     *PerformanceModel      `tfsdk:"performance"`
+}
+
+// serverBlock maps the model onto the shared server block renderer.
+func (m SiteResourceModel) serverBlock() serverBlock {
+	return serverBlock{
+		ListenPort: m.ListenPort,
+		ServerName: m.ServerName,
+		Root:       m.Root,
+		Access:     m.Access,
+		Headers:    m.Headers,
+		Security:   m.SecurityHeaders,
+		Directives: m.Directives,
+		Locations:  m.Locations,
+
+		Performance: m.Performance,
+	}
 }
 
 func (r *SiteResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -45,6 +80,7 @@ func (r *SiteResource) Metadata(ctx context.Context, req resource.MetadataReques
 
 func (r *SiteResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:             1,
 		MarkdownDescription: "Site resource",
 
 		Attributes: map[string]schema.Attribute{
@@ -55,27 +91,42 @@ func (r *SiteResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 			"server_name": schema.StringAttribute{
 				MarkdownDescription: "The name of the server.",
 				Optional:            true,
+				Validators: []validator.String{
+					serverNameValidator{},
+				},
 			},
 			"listen_port": schema.Int64Attribute{
 				MarkdownDescription: "The port the site listens on.",
 				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
 			},
 			"root": schema.StringAttribute{
 				MarkdownDescription: "The root directory of the site.",
 				Optional:            true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
 			},
 			"path": schema.StringAttribute{
 				MarkdownDescription: "The path of the site configuration file.",
-				Optional:            true,
-			},
-			"content": schema.StringAttribute{
-				MarkdownDescription: "The content of the site.",
-				Computed:            true,
-				Optional:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(), // Trigger replacement if it changes
+				Required:            true,
+				Validators: []validator.String{
+					pathValidator{},
 				},
 			},
+			"content":          contentAttribute(append(serverAttributes, "locations", "performance")),
+			"store_content":    storeContentAttribute(),
+			"content_sha256":   contentSHA256Attribute(),
+			"size":             sizeAttribute(),
+			"mtime":            mtimeAttribute(),
+			"access":           accessAttribute("server"),
+			"headers":          headersAttribute("server"),
+			"security_headers": securityHeadersAttribute(),
+			"directives":       extraDirectivesAttribute("at the end of the server block"),
+			"locations":        locationsAttribute(),
+			"performance":      performanceAttribute(),
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The ID of the site resource.",
@@ -87,23 +138,122 @@ func (r *SiteResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 	}
 }
 
+// ValidateConfig checks the extra directives and the content set by the
+// user against the directive catalog.
+func (r *SiteResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SiteResourceModel
+
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateExtraDirectives(data.Directives, contextServer, path.Root("directives"))...)
+	for i, location := range data.Locations {
+		resp.Diagnostics.Append(validateExtraDirectives(location.Directives, contextLocation, path.Root("locations").AtListIndex(i).AtName("directives"))...)
+	}
+	resp.Diagnostics.Append(validateContent(data.Content, path.Root("content"))...)
+}
+
+// ConfigValidators requires the listen port and server name to be set
+// together, since one is rarely useful without the other.
+func (r *SiteResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("listen_port"),
+			path.MatchRoot("server_name"),
+		),
+	}
+}
+
+// UpgradeState upgrades state from version 0, which predates the structured
+// attributes and checksums. The content it held is dropped, since content is
+// only kept in state on request; the next refresh records the checksum of
+// the file instead.
+func (r *SiteResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: serverSchemaV0("site_name"),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				prior, diags := readServerStateV0(ctx, req.State, "site_name")
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				upgraded := SiteResourceModel{
+					SiteName:      prior.Name,
+					ServerName:    prior.ServerName,
+					ListenPort:    prior.ListenPort,
+					Root:          prior.Root,
+					Path:          prior.Path,
+					Content:       types.StringNull(),
+					StoreContent:  types.BoolValue(false),
+					ContentSHA256: types.StringNull(),
+					Size:          types.Int64Null(),
+					Mtime:         types.StringNull(),
+					Id:            prior.Id,
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &upgraded)...)
+			},
+		},
+	}
+}
+
 func (r *SiteResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Use the SSH client passed from the provider
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*ssh.Client) // Type assertion to retrieve the SSH client
+	data, ok := req.ProviderData.(*resourceData) // Type assertion to retrieve the SSH client
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.configDirectories = data.configDirectories
+}
+
+// ModifyPlan renders the planned configuration to plan its checksum and
+// show the change it makes to the file on the host.
+func (r *SiteResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Configuration files may only be written below the configured directories
+	resp.Diagnostics.Append(checkConfigPath(ctx, req.Plan, r.configDirectories)...)
+
+	// Nothing to render on destroy or while the configuration is not known
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() || r.client == nil {
+		return
+	}
+
+	var plan SiteResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, diags := fileContent(ctx, req.Config, func() string {
+		return r.render(plan, &resp.Diagnostics)
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planFile(ctx, req, resp, content)
+	if planChanges(ctx, req, resp, "content_sha256") {
+		resp.Diagnostics.Append(planDiff(r.client.(*ssh.Client), plan.Path.ValueString(), content, false)...)
+	}
 }
 
 func (r *SiteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -115,48 +265,41 @@ func (r *SiteResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	// Build the NGINX server block content
-	configContent := fmt.Sprintf(`
-	server {
-		listen %d;
-		server_name %s;
-
-		root %s;
-		index index.html;
-
-		location / {
-			try_files $uri $uri/ =404;
-		}
-	}`, data.ListenPort.ValueInt64(), data.ServerName.ValueString(), data.Root.ValueString())
+	// Use the configured content verbatim, or build the NGINX server block
+	configContent, diags := fileContent(ctx, req.Config, func() string {
+		return r.render(data, &resp.Diagnostics)
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Use SSH to write the content to the file
+	// Write the configuration, keeping the previous file if NGINX rejects it
 	sshClient := r.client.(*ssh.Client)
-
-	session, err := sshClient.NewSession()
-	if err != nil {
+	if err := applyConfigFile(sshClient, data.Path.ValueString(), configContent); err != nil {
 		resp.Diagnostics.AddError(
-			"SSH Session Error",
-			fmt.Sprintf("Failed to create SSH session: %s", err),
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", data.Path.ValueString(), err),
 		)
 		return
 	}
-	defer session.Close()
-
-	command := fmt.Sprintf("echo '%s' | sudo tee %s > /dev/null", shellEscape(configContent), data.Path.ValueString())
 
-	if err := session.Run(command); err != nil {
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Command Execution Error",
-			fmt.Sprintf("Failed to execute command: %s", err),
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
 		)
 		return
 	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
 
 	// Set the resource ID to the site_name
 	data.Id = types.StringValue(data.SiteName.ValueString())
 
-	// Explicitly set the content
-	data.Content = types.StringValue(configContent)
+	// Keep the content in state only when requested
+	data.Content = appliedContent(data.Content, data.StoreContent, configContent)
 
 	// Save the data into the Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -167,6 +310,33 @@ func (r *SiteResource) Create(ctx context.Context, req resource.CreateRequest, r
 	tflog.Trace(ctx, fmt.Sprintf("Created site resource: %s", data.SiteName.ValueString()))
 }
 
+// render builds the server block for data. Brotli is only rendered when the
+// host can serve it; otherwise a warning is added and gzip is used alone.
+func (r *SiteResource) render(data SiteResourceModel, diags *diag.Diagnostics) string {
+	block := data.serverBlock()
+
+	if data.Performance.wantsBrotli() {
+		available, err := hasBrotliModule(r.client.(*ssh.Client))
+		if err != nil {
+			diags.AddError(
+				"Command Execution Error",
+				fmt.Sprintf("Failed to detect the brotli module: %s", err),
+			)
+			return ""
+		}
+		if !available {
+			diags.AddAttributeWarning(
+				path.Root("performance").AtName("compression").AtName("brotli"),
+				"Brotli Not Available",
+				"The brotli module is neither built into NGINX on the host nor loaded with load_module, so only gzip compression is rendered.",
+			)
+		}
+		block.Brotli = available
+	}
+
+	return renderConfig(block.directives())
+}
+
 func (r *SiteResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data SiteResourceModel
 
@@ -176,33 +346,19 @@ func (r *SiteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// Connect to the SSH server
+	// Read the remote file over SSH
 	sshClient := r.client.(*ssh.Client)
-	session, err := sshClient.NewSession()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"SSH Session Error",
-			fmt.Sprintf("Failed to create SSH session: %s", err),
-		)
-		return
-	}
-	defer session.Close()
-
-	// Build the command to read the remote file
-	command := fmt.Sprintf("if [ -f %s ]; then cat %s; else echo 'NOT_FOUND'; fi", data.Path.ValueString(), data.Path.ValueString())
-
-	// Execute the command
-	output, err := session.Output(command)
+	remote, found, err := readRemoteFile(sshClient, data.Path.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Command Execution Error",
-			fmt.Sprintf("Failed to execute command '%s': %s", command, err),
+			fmt.Sprintf("Failed to read %s: %s", data.Path.ValueString(), err),
 		)
 		return
 	}
 
 	// Handle missing file case
-	if strings.TrimSpace(string(output)) == "NOT_FOUND" {
+	if !found {
 		resp.Diagnostics.AddWarning(
 			"Resource Not Found",
 			fmt.Sprintf("The file at path '%s' does not exist. Terraform will remove it from the state.", data.Path.ValueString()),
@@ -211,8 +367,40 @@ func (r *SiteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// Update the content field in the state
-	data.Content = types.StringValue(string(output))
+	// Refresh the typed attributes from the server block on the host,
+	// unless the content is written verbatim and has none
+	if data.Content.IsNull() || !data.serverBlock().empty() {
+		// Compare with brotli only when the host can serve it, as rendering did
+		block := data.serverBlock()
+		if data.Performance.wantsBrotli() {
+			available, err := hasBrotliModule(sshClient)
+			block.Brotli = err == nil && available
+		}
+
+		settings, ok, diags := refreshServer(block.directives(), remote)
+		resp.Diagnostics.Append(diags...)
+		if ok {
+			data.ServerName = settings.serverName(data.ServerName)
+			data.ListenPort = settings.ListenPort
+			data.Root = settings.Root
+		}
+	}
+
+	// Keep the stored content unless the host holds a different configuration
+	if !data.Content.IsNull() {
+		data.Content = types.StringValue(refreshContent(data.Content.ValueString(), remote))
+	}
+
+	// Refresh the checksum, which plans a rewrite when the file changed
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
 
 	// Save the updated state back to Terraform
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -221,8 +409,8 @@ func (r *SiteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	tflog.Debug(ctx, "Successfully read nginx_site resource state", map[string]interface{}{
-		"path":    data.Path.ValueString(),
-		"content": data.Content.ValueString(),
+		"path":           data.Path.ValueString(),
+		"content_sha256": data.ContentSHA256.ValueString(),
 	})
 }
 
@@ -242,47 +430,41 @@ func (r *SiteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	// Build the updated NGINX configuration
-	updatedConfig := fmt.Sprintf(`
-	server {
-		listen %d;
-		server_name %s;
-
-		root %s;
-		index index.html;
-
-		location / {
-			try_files $uri $uri/ =404;
-		}
-	}`, plan.ListenPort.ValueInt64(), plan.ServerName.ValueString(), plan.Root.ValueString())
+	// Use the configured content verbatim, or build the updated NGINX configuration
+	updatedConfig, diags := fileContent(ctx, req.Config, func() string {
+		return r.render(plan, &resp.Diagnostics)
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Use SSH to update the file content
+	// Write the configuration, keeping the previous file if NGINX rejects it
 	sshClient := r.client.(*ssh.Client)
-	session, err := sshClient.NewSession()
-	if err != nil {
+	if err := applyConfigFile(sshClient, plan.Path.ValueString(), updatedConfig); err != nil {
 		resp.Diagnostics.AddError(
-			"SSH Session Error",
-			fmt.Sprintf("Failed to create SSH session: %s", err),
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", plan.Path.ValueString(), err),
 		)
 		return
 	}
-	defer session.Close()
-
-	command := fmt.Sprintf("echo '%s' | sudo tee %s > /dev/null", shellEscape(updatedConfig), plan.Path.ValueString())
 
-	if err := session.Run(command); err != nil {
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, plan.Path.ValueString())
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Command Execution Error",
-			fmt.Sprintf("Failed to execute command: %s", err),
+			fmt.Sprintf("Failed to read the checksum of %s: %s", plan.Path.ValueString(), err),
 		)
 		return
 	}
+	plan.ContentSHA256, plan.Size, plan.Mtime = fileAttributes(info, found)
 
 	// Set the resource ID to the stable site_name
 	plan.Id = types.StringValue(plan.SiteName.ValueString())
 
-	// Set the content to the updated configuration
-	plan.Content = types.StringValue(updatedConfig)
+	// Keep the content in state only when requested
+	plan.Content = appliedContent(plan.Content, plan.StoreContent, updatedConfig)
 
 	// Save the updated state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -302,34 +484,26 @@ func (r *SiteResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	// Use SSH to delete the site config file
-	sshClient := r.client.(*ssh.Client)
-	session, err := sshClient.NewSession()
+	// Remove the configuration file, then validate and reload NGINX
+	found, err := deleteConfigFile(r.client.(*ssh.Client), data.Path.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"SSH Session Error",
-			fmt.Sprintf("Failed to create SSH session: %s", err),
-		)
-		return
-	}
-	defer session.Close()
-
-	command := fmt.Sprintf("sudo rm -f %s", data.Path.ValueString())
-	if err := session.Run(command); err != nil {
 		resp.Diagnostics.AddError(
 			"Command Execution Error",
 			fmt.Sprintf("Failed to delete file at %s: %s", data.Path.ValueString(), err),
 		)
 		return
 	}
+	if !found {
+		tflog.Trace(ctx, fmt.Sprintf("Configuration file already removed from the host: %s", data.Path.ValueString()))
+	}
 
 	tflog.Trace(ctx, fmt.Sprintf("Deleted site resource: %s", data.SiteName.ValueString()))
 }
 
 func (r *SiteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import by path or name
+	// Import by name and path
 	idParts := strings.Split(req.ID, ":")
-	if len(idParts) != 2 {
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 		resp.Diagnostics.AddError(
 			"Invalid Import ID",
 			"Expected import ID in format 'site_name:path'",
@@ -337,10 +511,27 @@ func (r *SiteResource) ImportState(ctx context.Context, req resource.ImportState
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("site_name"), idParts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), idParts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[0])...)
+	// Reconstruct the typed attributes from the configuration on the host
+	imported, diags := importServer(r.client.(*ssh.Client), idParts[1], true)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Read the existing configuration to populate other attributes
-	// This would require additional code to parse the NGINX config
+	data := SiteResourceModel{
+		SiteName:     types.StringValue(idParts[0]),
+		Path:         types.StringValue(idParts[1]),
+		Id:           types.StringValue(idParts[0]),
+		ServerName:   imported.Settings.ServerName,
+		ListenPort:   imported.Settings.ListenPort,
+		Root:         imported.Settings.Root,
+		Access:       imported.Access,
+		Headers:      imported.Headers,
+		Directives:   imported.Directives,
+		Locations:    imported.Locations,
+		Content:      types.StringNull(),
+		StoreContent: types.BoolValue(false),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -0,0 +1,398 @@
+package nginx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &StatusDataSource{}
+
+// defaultStubStatusURL is where the stub_status page is queried when no URL
+// is configured.
+const defaultStubStatusURL = "http://127.0.0.1/nginx_status"
+
+// statusTimeout bounds every request made by the status data source.
+const statusTimeout = 10 * time.Second
+
+// maxStatusResponse bounds the size of the responses read by the status data
+// source.
+const maxStatusResponse = 4 << 20
+
+func NewStatusDataSource() datasource.DataSource {
+	return &StatusDataSource{}
+}
+
+// StatusDataSource defines the data source implementation.
+type StatusDataSource struct {
+	client interface{} // Use interface{} to accept SSH client passed from provider.go
+}
+
+// StatusDataSourceModel describes the data source data model.
+type StatusDataSourceModel struct {
+	StubStatusURL     types.String    `tfsdk:"stub_status_url"`
+	APIURL            types.String    `tfsdk:"api_url"`
+	ActiveConnections types.Int64     `tfsdk:"active_connections"`
+	Accepts           types.Int64     `tfsdk:"accepts"`
+	Handled           types.Int64     `tfsdk:"handled"`
+	Requests          types.Int64     `tfsdk:"requests"`
+	Reading           types.Int64     `tfsdk:"reading"`
+	Writing           types.Int64     `tfsdk:"writing"`
+	Waiting           types.Int64     `tfsdk:"waiting"`
+	Upstreams         []UpstreamModel `tfsdk:"upstreams"`
+	Id                types.String    `tfsdk:"id"`
+}
+
+// UpstreamModel describes an upstream group reported by the NGINX Plus API.
+type UpstreamModel struct {
+	Name         types.String        `tfsdk:"name"`
+	HealthyPeers types.Int64         `tfsdk:"healthy_peers"`
+	Peers        []UpstreamPeerModel `tfsdk:"peers"`
+}
+
+// UpstreamPeerModel describes a server of an upstream group.
+type UpstreamPeerModel struct {
+	Server   types.String `tfsdk:"server"`
+	Name     types.String `tfsdk:"name"`
+	State    types.String `tfsdk:"state"`
+	Backup   types.Bool   `tfsdk:"backup"`
+	Active   types.Int64  `tfsdk:"active"`
+	Requests types.Int64  `tfsdk:"requests"`
+	Fails    types.Int64  `tfsdk:"fails"`
+	Unavail  types.Int64  `tfsdk:"unavail"`
+}
+
+// stubStatus holds the counters of the stub_status page.
+type stubStatus struct {
+	active, accepts, handled, requests, reading, writing, waiting int64
+}
+
+// plusUpstream is an upstream group as returned by the NGINX Plus API.
+type plusUpstream struct {
+	Peers []struct {
+		Server   string `json:"server"`
+		Name     string `json:"name"`
+		State    string `json:"state"`
+		Backup   bool   `json:"backup"`
+		Active   int64  `json:"active"`
+		Requests int64  `json:"requests"`
+		Fails    int64  `json:"fails"`
+		Unavail  int64  `json:"unavail"`
+	} `json:"peers"`
+}
+
+func (d *StatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_status"
+}
+
+func (d *StatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	counter := func(description string) schema.Int64Attribute {
+		return schema.Int64Attribute{
+			MarkdownDescription: description,
+			Computed:            true,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the runtime status of NGINX from the `stub_status` page and, when configured, " +
+			"the upstream health from the NGINX Plus API. The requests are made through the SSH connection, so URLs " +
+			"are resolved on the host and `127.0.0.1` is the host itself.",
+
+		Attributes: map[string]schema.Attribute{
+			"stub_status_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the `stub_status` page. Defaults to `" + defaultStubStatusURL + "`.",
+				Optional:            true,
+			},
+			"api_url": schema.StringAttribute{
+				MarkdownDescription: "The base URL of the NGINX Plus API, such as `http://127.0.0.1:8080/api`. " +
+					"`upstreams` is only read when this is set.",
+				Optional: true,
+			},
+			"active_connections": counter("The current number of client connections, including waiting ones."),
+			"accepts":            counter("The total number of accepted client connections."),
+			"handled":            counter("The total number of handled connections."),
+			"requests":           counter("The total number of client requests."),
+			"reading":            counter("The current number of connections where the request header is being read."),
+			"writing":            counter("The current number of connections where the response is being written."),
+			"waiting":            counter("The current number of idle client connections waiting for a request."),
+			"upstreams": schema.ListNestedAttribute{
+				MarkdownDescription: "The upstream groups reported by the NGINX Plus API, sorted by name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the upstream group.",
+							Computed:            true,
+						},
+						"healthy_peers": counter("The number of peers in the `up` state."),
+						"peers": schema.ListNestedAttribute{
+							MarkdownDescription: "The servers of the upstream group.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"server": schema.StringAttribute{
+										MarkdownDescription: "The address of the server.",
+										Computed:            true,
+									},
+									"name": schema.StringAttribute{
+										MarkdownDescription: "The name of the server as configured.",
+										Computed:            true,
+									},
+									"state": schema.StringAttribute{
+										MarkdownDescription: "The state of the server: `up`, `draining`, `down`, " +
+											"`unavail`, `checking` or `unhealthy`.",
+										Computed: true,
+									},
+									"backup": schema.BoolAttribute{
+										MarkdownDescription: "Whether the server is a backup server.",
+										Computed:            true,
+									},
+									"active":   counter("The current number of active connections."),
+									"requests": counter("The total number of client requests forwarded to the server."),
+									"fails":    counter("The total number of unsuccessful attempts to reach the server."),
+									"unavail":  counter("How many times the server became unavailable."),
+								},
+							},
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The URL of the `stub_status` page.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *StatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ssh.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ssh.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *StatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data StatusDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpClient := tunnelHTTPClient(d.client.(*ssh.Client))
+	defer httpClient.CloseIdleConnections()
+
+	stubStatusURL := defaultStubStatusURL
+	if !data.StubStatusURL.IsNull() {
+		stubStatusURL = data.StubStatusURL.ValueString()
+	}
+
+	body, err := getStatus(ctx, httpClient, stubStatusURL)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Status Request Error",
+			fmt.Sprintf("Failed to read the stub_status page at %s: %s", stubStatusURL, err),
+		)
+		return
+	}
+	status, err := parseStubStatus(string(body))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Status Request Error",
+			fmt.Sprintf("Failed to parse the stub_status page at %s: %s", stubStatusURL, err),
+		)
+		return
+	}
+
+	data.ActiveConnections = types.Int64Value(status.active)
+	data.Accepts = types.Int64Value(status.accepts)
+	data.Handled = types.Int64Value(status.handled)
+	data.Requests = types.Int64Value(status.requests)
+	data.Reading = types.Int64Value(status.reading)
+	data.Writing = types.Int64Value(status.writing)
+	data.Waiting = types.Int64Value(status.waiting)
+	data.Id = types.StringValue(stubStatusURL)
+
+	if !data.APIURL.IsNull() {
+		upstreams, err := readPlusUpstreams(ctx, httpClient, data.APIURL.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Status Request Error",
+				fmt.Sprintf("Failed to read the upstreams from the NGINX Plus API at %s: %s", data.APIURL.ValueString(), err),
+			)
+			return
+		}
+		data.Upstreams = upstreams
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Read NGINX status: %d active connections", status.active))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// tunnelHTTPClient returns an HTTP client that connects through the SSH
+// connection, so that requests are made from the host.
+func tunnelHTTPClient(client *ssh.Client) *http.Client {
+	return &http.Client{
+		Timeout: statusTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+				return client.DialContext(ctx, network, address)
+			},
+		},
+	}
+}
+
+// getStatus fetches url and returns the response body, failing on any status
+// other than 200 OK.
+func getStatus(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxStatusResponse))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response %s", resp.Status)
+	}
+
+	return body, nil
+}
+
+// parseStubStatus parses the stub_status page:
+//
+//	Active connections: 291
+//	server accepts handled requests
+//	 16630948 16630948 31070465
+//	Reading: 6 Writing: 179 Waiting: 106
+func parseStubStatus(page string) (stubStatus, error) {
+	fields := strings.Fields(page)
+	if len(fields) != 16 || fields[0] != "Active" || fields[3] != "server" || fields[10] != "Reading:" {
+		return stubStatus{}, fmt.Errorf("unexpected content %q", page)
+	}
+
+	values := make([]int64, 0, 7)
+	for _, index := range []int{2, 7, 8, 9, 11, 13, 15} {
+		value, err := strconv.ParseInt(fields[index], 10, 64)
+		if err != nil {
+			return stubStatus{}, fmt.Errorf("unexpected counter %q", fields[index])
+		}
+		values = append(values, value)
+	}
+
+	return stubStatus{
+		active:   values[0],
+		accepts:  values[1],
+		handled:  values[2],
+		requests: values[3],
+		reading:  values[4],
+		writing:  values[5],
+		waiting:  values[6],
+	}, nil
+}
+
+// readPlusUpstreams reads the upstream groups from the NGINX Plus API at
+// baseURL, using the newest API version it offers.
+func readPlusUpstreams(ctx context.Context, client *http.Client, baseURL string) ([]UpstreamModel, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	body, err := getStatus(ctx, client, baseURL+"/")
+	if err != nil {
+		return nil, err
+	}
+	var versions []int
+	if err := json.Unmarshal(body, &versions); err != nil || len(versions) == 0 {
+		return nil, fmt.Errorf("unexpected API versions %q", body)
+	}
+
+	body, err = getStatus(ctx, client, fmt.Sprintf("%s/%d/http/upstreams", baseURL, slices.Max(versions)))
+	if err != nil {
+		return nil, err
+	}
+	var upstreams map[string]plusUpstream
+	if err := json.Unmarshal(body, &upstreams); err != nil {
+		return nil, fmt.Errorf("unexpected upstreams: %w", err)
+	}
+
+	return upstreamModels(upstreams), nil
+}
+
+// upstreamModels converts the upstream groups returned by the NGINX Plus API
+// into their models, sorted by name.
+func upstreamModels(upstreams map[string]plusUpstream) []UpstreamModel {
+	names := make([]string, 0, len(upstreams))
+	for name := range upstreams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	models := make([]UpstreamModel, 0, len(names))
+	for _, name := range names {
+		model := UpstreamModel{
+			Name:  types.StringValue(name),
+			Peers: []UpstreamPeerModel{},
+		}
+
+		healthy := int64(0)
+		for _, peer := range upstreams[name].Peers {
+			if peer.State == "up" {
+				healthy++
+			}
+			model.Peers = append(model.Peers, UpstreamPeerModel{
+				Server:   types.StringValue(peer.Server),
+				Name:     types.StringValue(peer.Name),
+				State:    types.StringValue(peer.State),
+				Backup:   types.BoolValue(peer.Backup),
+				Active:   types.Int64Value(peer.Active),
+				Requests: types.Int64Value(peer.Requests),
+				Fails:    types.Int64Value(peer.Fails),
+				Unavail:  types.Int64Value(peer.Unavail),
+			})
+		}
+		model.HealthyPeers = types.Int64Value(healthy)
+
+		models = append(models, model)
+	}
+
+	return models
+}
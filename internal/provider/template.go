@@ -0,0 +1,131 @@
+package nginx
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// templateFuncs are the NGINX-aware helpers available to templates.
+var templateFuncs = template.FuncMap{
+	"quote":       templateQuote,
+	"indent":      templateIndent,
+	"join_listen": templateJoinListen,
+}
+
+// renderTemplate executes the text/template text with vars as its data.
+// Referring to a variable that is not set is an error.
+func renderTemplate(name string, text string, vars interface{}) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// templateQuote quotes value as a single NGINX argument.
+func templateQuote(value interface{}) string {
+	return quoteArg(fmt.Sprint(value))
+}
+
+// templateIndent indents every non-empty line of text by depth tabs, the
+// indentation the provider renders nested blocks with.
+func templateIndent(depth int, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = strings.Repeat("\t", depth) + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// templateJoinListen renders one listen directive per address, each with
+// the given parameters, such as ssl or default_server. addresses is a
+// single address or port, or a list of them.
+func templateJoinListen(addresses interface{}, params ...string) string {
+	list, ok := addresses.([]interface{})
+	if !ok {
+		list = []interface{}{addresses}
+	}
+
+	directives := make([]*Directive, 0, len(list))
+	for _, address := range list {
+		directives = append(directives, simpleDirective("listen", append([]string{fmt.Sprint(address)}, params...)...))
+	}
+	return strings.TrimSuffix(renderConfig(directives), "\n")
+}
+
+// templateValue converts a Terraform value into the Go value templates see:
+// strings, numbers and bools as themselves, lists, sets and tuples as
+// slices, and maps and objects as maps.
+func templateValue(value tftypes.Value) (interface{}, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+
+	switch t := value.Type(); {
+	case t.Equal(tftypes.String):
+		var s string
+		err := value.As(&s)
+		return s, err
+
+	case t.Equal(tftypes.Bool):
+		var b bool
+		err := value.As(&b)
+		return b, err
+
+	case t.Equal(tftypes.Number):
+		var number big.Float
+		if err := value.As(&number); err != nil {
+			return nil, err
+		}
+		if number.IsInt() {
+			if i, accuracy := number.Int64(); accuracy == big.Exact {
+				return i, nil
+			}
+		}
+		f, _ := number.Float64()
+		return f, nil
+
+	case isListLike(t):
+		var elements []tftypes.Value
+		if err := value.As(&elements); err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, 0, len(elements))
+		for _, element := range elements {
+			converted, err := templateValue(element)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+
+	case isMapLike(t):
+		var elements map[string]tftypes.Value
+		if err := value.As(&elements); err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, len(elements))
+		for key, element := range elements {
+			converted, err := templateValue(element)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = converted
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("unsupported value of type %s", value.Type())
+}
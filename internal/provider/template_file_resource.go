@@ -0,0 +1,421 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TemplateFileResource{}
+var _ resource.ResourceWithModifyPlan = &TemplateFileResource{}
+var _ resource.ResourceWithValidateConfig = &TemplateFileResource{}
+var _ resource.ResourceWithConfigValidators = &TemplateFileResource{}
+
+func NewTemplateFileResource() resource.Resource {
+	return &TemplateFileResource{}
+}
+
+// TemplateFileResource defines the resource implementation.
+type TemplateFileResource struct {
+	client            interface{} // Use interface{} to accept SSH client passed from provider.go
+	configDirectories []string
+}
+
+// TemplateFileResourceModel describes the resource data model.
+type TemplateFileResourceModel struct {
+	Path          types.String  `tfsdk:"path"`
+	Template      types.String  `tfsdk:"template"`
+	TemplateFile  types.String  `tfsdk:"template_file"`
+	Vars          types.Dynamic `tfsdk:"vars"`
+	Context       types.String  `tfsdk:"context"`
+	Content       types.String  `tfsdk:"content"`
+	StoreContent  types.Bool    `tfsdk:"store_content"`
+	KeepOnDestroy types.Bool    `tfsdk:"keep_on_destroy"`
+	ContentSHA256 types.String  `tfsdk:"content_sha256"`
+	Size          types.Int64   `tfsdk:"size"`
+	Mtime         types.String  `tfsdk:"mtime"`
+	Id            types.String  `tfsdk:"id"`
+}
+
+func (r *TemplateFileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template_file"
+}
+
+func (r *TemplateFileResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Writes a configuration file rendered from a Go `text/template`, for layouts the " +
+			"structured resources do not cover. The rendered configuration is checked against the directive " +
+			"catalog at plan time, then written, tested with `nginx -t` and reloaded; the previous file is " +
+			"restored when the test fails.\n\n" +
+			"Besides the built-in template functions, templates can use `quote`, which quotes a value as a single " +
+			"NGINX argument, `indent`, which indents every line of a text by a number of tabs, and `join_listen`, " +
+			"which renders a `listen` directive for each of a list of addresses, followed by the given parameters, " +
+			"as in `{{ join_listen .ports \"ssl\" }}`.",
+
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The path of the configuration file on the host. Changing it replaces the file.",
+				Required:            true,
+				Validators: []validator.String{
+					pathValidator{},
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"template": schema.StringAttribute{
+				MarkdownDescription: "The template. Exactly one of `template` and `template_file` must be set.",
+				Optional:            true,
+			},
+			"template_file": schema.StringAttribute{
+				MarkdownDescription: "The path of a template file on the machine running Terraform. Changes to the " +
+					"file are planned like changes to `template`.",
+				Optional: true,
+			},
+			"vars": schema.DynamicAttribute{
+				MarkdownDescription: "The variables the template refers to as `.name`: an object or map whose values " +
+					"may be strings, numbers, bools, lists and nested objects. Referring to a variable that is not " +
+					"set is an error.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"context": schema.StringAttribute{
+				MarkdownDescription: "The context the file is included in, used to validate the rendered " +
+					"configuration: `main`, `events`, `http`, `server`, `location`, `upstream` or `stream`. " +
+					"Defaults to `http`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("http"),
+				Validators: []validator.String{
+					stringvalidator.OneOf(sortedContextNames()...),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "The rendered configuration, kept only when `store_content` is set.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"store_content":   storeContentAttribute(),
+			"keep_on_destroy": keepOnDestroyAttribute(),
+			"content_sha256":  contentSHA256Attribute(),
+			"size":            sizeAttribute(),
+			"mtime":           mtimeAttribute(),
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The path of the configuration file.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// render renders the template with the variables.
+func (m TemplateFileResourceModel) render(ctx context.Context) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	source := m.templateSource()
+	name, text := "template", m.Template.ValueString()
+	if !m.TemplateFile.IsNull() {
+		name = m.TemplateFile.ValueString()
+		data, err := os.ReadFile(name)
+		if err != nil {
+			diags.AddAttributeError(source, "Template Error", fmt.Sprintf("Failed to read %s: %s", name, err))
+			return "", diags
+		}
+		text = string(data)
+	}
+
+	vars, err := m.templateVars(ctx)
+	if err != nil {
+		diags.AddAttributeError(path.Root("vars"), "Invalid Template Variables", err.Error())
+		return "", diags
+	}
+
+	rendered, err := renderTemplate(name, text, vars)
+	if err != nil {
+		diags.AddAttributeError(source, "Template Error", fmt.Sprintf("Failed to render the template: %s", err))
+		return "", diags
+	}
+
+	return rendered, diags
+}
+
+// templateSource returns the attribute the template comes from.
+func (m TemplateFileResourceModel) templateSource() path.Path {
+	if !m.TemplateFile.IsNull() {
+		return path.Root("template_file")
+	}
+	return path.Root("template")
+}
+
+// templateVars converts vars into the data the template is executed with.
+func (m TemplateFileResourceModel) templateVars(ctx context.Context) (map[string]interface{}, error) {
+	if m.Vars.IsNull() || m.Vars.IsUnderlyingValueNull() {
+		return map[string]interface{}{}, nil
+	}
+
+	value, err := m.Vars.UnderlyingValue().ToTerraformValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := templateValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, ok := converted.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("vars must be an object or a map")
+	}
+	return vars, nil
+}
+
+// ValidateConfig renders the template and checks the result against the
+// directive catalog, so that mistakes show up before anything is written.
+func (r *TemplateFileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data TemplateFileResourceModel
+
+	if !req.Config.Raw.IsFullyKnown() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Leave a missing template to the config validators
+	if data.Template.IsNull() == data.TemplateFile.IsNull() {
+		return
+	}
+
+	rendered, diags := data.render(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scope := contextHTTP
+	if !data.Context.IsNull() {
+		scope = contextNames[data.Context.ValueString()]
+	}
+	resp.Diagnostics.Append(validateConfigText(rendered, scope, data.templateSource())...)
+}
+
+// ConfigValidators requires the template to come from exactly one source.
+func (r *TemplateFileResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("template"),
+			path.MatchRoot("template_file"),
+		),
+	}
+}
+
+func (r *TemplateFileResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Use the SSH client passed from the provider
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*resourceData) // Type assertion to retrieve the SSH client
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *resourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.configDirectories = data.configDirectories
+}
+
+// ModifyPlan renders the template to plan its checksum and show the change
+// it makes to the file on the host.
+func (r *TemplateFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Configuration files may only be written below the configured directories
+	resp.Diagnostics.Append(checkConfigPath(ctx, req.Plan, r.configDirectories)...)
+
+	// Nothing to render on destroy or while the configuration is not known
+	if req.Plan.Raw.IsNull() || !req.Config.Raw.IsFullyKnown() || r.client == nil {
+		return
+	}
+
+	var plan TemplateFileResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content, diags := plan.render(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planFile(ctx, req, resp, content)
+	if planChanges(ctx, req, resp, "content_sha256") {
+		resp.Diagnostics.Append(planDiff(r.client.(*ssh.Client), plan.Path.ValueString(), content, true)...)
+	}
+}
+
+func (r *TemplateFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data TemplateFileResourceModel
+
+	// Retrieve the plan data
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save the data into the Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Created Template File resource: %s", data.Path.ValueString()))
+}
+
+func (r *TemplateFileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data TemplateFileResourceModel
+
+	// Retrieve the current state
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Refresh the checksum, which plans a rewrite when the file changed
+	info, found, err := statRemoteFile(r.client.(*ssh.Client), data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddWarning(
+			"File Not Found",
+			fmt.Sprintf("The file at path '%s' does not exist.", data.Path.ValueString()),
+		)
+	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
+
+	// Save the updated state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TemplateFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan TemplateFileResourceModel
+
+	// Retrieve the updated plan data
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save the updated state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Updated Template File resource: %s", plan.Path.ValueString()))
+}
+
+// apply renders the template, writes it to the host, then validates and
+// reloads NGINX, and records the resulting file attributes in data.
+func (r *TemplateFileResource) apply(ctx context.Context, data *TemplateFileResourceModel, diags *diag.Diagnostics) {
+	content, renderDiags := data.render(ctx)
+	diags.Append(renderDiags...)
+	if diags.HasError() {
+		return
+	}
+
+	// Write the configuration, keeping the previous file if NGINX rejects it
+	sshClient := r.client.(*ssh.Client)
+	if err := applyConfigFile(sshClient, data.Path.ValueString(), content); err != nil {
+		diags.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to apply %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+
+	// Record the checksum of the file as written
+	info, found, err := statRemoteFile(sshClient, data.Path.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to read the checksum of %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	data.ContentSHA256, data.Size, data.Mtime = fileAttributes(info, found)
+
+	data.Id = types.StringValue(data.Path.ValueString())
+
+	// Keep the content in state only when requested
+	data.Content = appliedContent(data.Content, data.StoreContent, content)
+}
+
+func (r *TemplateFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data TemplateFileResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.KeepOnDestroy.ValueBool() {
+		tflog.Trace(ctx, fmt.Sprintf("Kept configuration file of Template File resource on the host: %s", data.Path.ValueString()))
+		return
+	}
+
+	// Remove the configuration file, then validate and reload NGINX
+	found, err := deleteConfigFile(r.client.(*ssh.Client), data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Execution Error",
+			fmt.Sprintf("Failed to delete file at %s: %s", data.Path.ValueString(), err),
+		)
+		return
+	}
+	if !found {
+		tflog.Trace(ctx, fmt.Sprintf("Configuration file already removed from the host: %s", data.Path.ValueString()))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Deleted Template File resource: %s", data.Path.ValueString()))
+}
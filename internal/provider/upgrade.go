@@ -0,0 +1,68 @@
+package nginx
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// serverStateV0 holds the version 0 state of the site, API, proxy and config
+// resources, which only differed in the name of their name attribute.
+type serverStateV0 struct {
+	Name       types.String
+	ServerName types.String
+	ListenPort types.Int64
+	Root       types.String
+	Path       types.String
+	Id         types.String
+}
+
+// serverSchemaV0 returns the version 0 schema of the site, API, proxy and
+// config resources, with nameAttribute as their name attribute.
+func serverSchemaV0(nameAttribute string) *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			nameAttribute: schema.StringAttribute{
+				Required: true,
+			},
+			"server_name": schema.StringAttribute{
+				Optional: true,
+			},
+			"listen_port": schema.Int64Attribute{
+				Optional: true,
+			},
+			"root": schema.StringAttribute{
+				Optional: true,
+			},
+			"path": schema.StringAttribute{
+				Optional: true,
+			},
+			"content": schema.StringAttribute{
+				Computed: true,
+				Optional: true,
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// readServerStateV0 reads the attributes of version 0 state.
+func readServerStateV0(ctx context.Context, state *tfsdk.State, nameAttribute string) (serverStateV0, diag.Diagnostics) {
+	var prior serverStateV0
+	var diags diag.Diagnostics
+
+	diags.Append(state.GetAttribute(ctx, path.Root(nameAttribute), &prior.Name)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("server_name"), &prior.ServerName)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("listen_port"), &prior.ListenPort)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("root"), &prior.Root)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("path"), &prior.Path)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("id"), &prior.Id)...)
+
+	return prior, diags
+}
@@ -0,0 +1,196 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// stateV0JSON is version 0 state, which stored the rendered configuration
+// in content.
+const stateV0JSON = `{
+	"%s": "example",
+	"server_name": "example.com",
+	"listen_port": 8080,
+	"root": "/var/www/example",
+	"path": "/etc/nginx/sites-enabled/example",
+	"content": "server {\n\tlisten 8080;\n}\n",
+	"id": "example"
+}`
+
+// stateV1JSON is current state of a resource that keeps its content.
+const stateV1JSON = `{
+	"%s": "example",
+	"server_name": "example.com",
+	"listen_port": 8080,
+	"root": "/var/www/example",
+	"path": "/etc/nginx/sites-enabled/example",
+	"content": "server {\n\tlisten 8080;\n}\n",
+	"store_content": true,
+	"keep_on_destroy": false,
+	"content_sha256": "0000000000000000000000000000000000000000000000000000000000000000",
+	"size": 23,
+	"id": "example"
+}`
+
+// wantState holds the attributes checked after an upgrade or move.
+type wantState struct {
+	name         string
+	serverName   string
+	listenPort   int64
+	content      types.String
+	storeContent bool
+}
+
+func newTestProviderServer(t *testing.T) tfprotov6.ProviderServer {
+	t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("creating the provider server: %s", err)
+	}
+	return server
+}
+
+// checkState decodes state with the schema of r and compares it with want.
+func checkState(t *testing.T, r resource.Resource, nameAttribute string, state *tfprotov6.DynamicValue, want wantState) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schema resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schema)
+
+	raw, err := state.Unmarshal(schema.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("decoding the state: %s", err)
+	}
+	decoded := tfsdk.State{Schema: schema.Schema, Raw: raw}
+
+	var name, serverName, content types.String
+	var listenPort types.Int64
+	var storeContent types.Bool
+	for attribute, target := range map[string]interface{}{
+		nameAttribute:   &name,
+		"server_name":   &serverName,
+		"listen_port":   &listenPort,
+		"content":       &content,
+		"store_content": &storeContent,
+	} {
+		if diags := decoded.GetAttribute(ctx, path.Root(attribute), target); diags.HasError() {
+			t.Fatalf("reading %s: %v", attribute, diags)
+		}
+	}
+
+	if name.ValueString() != want.name {
+		t.Errorf("%s = %s, want %q", nameAttribute, name, want.name)
+	}
+	if serverName.ValueString() != want.serverName {
+		t.Errorf("server_name = %s, want %q", serverName, want.serverName)
+	}
+	if listenPort.ValueInt64() != want.listenPort {
+		t.Errorf("listen_port = %s, want %d", listenPort, want.listenPort)
+	}
+	if !content.Equal(want.content) {
+		t.Errorf("content = %s, want %s", content, want.content)
+	}
+	if storeContent.ValueBool() != want.storeContent {
+		t.Errorf("store_content = %s, want %t", storeContent, want.storeContent)
+	}
+}
+
+// TestUpgradeStateV0 feeds version 0 state through the upgrader of every
+// server block resource. The content is dropped, as it is now only stored
+// on request.
+func TestUpgradeStateV0(t *testing.T) {
+	tests := []struct {
+		typeName      string
+		nameAttribute string
+		resource      func() resource.Resource
+	}{
+		{"nginx_site", "site_name", NewSiteResource},
+		{"nginx_api", "api_name", NewAPIResource},
+		{"nginx_proxy", "proxy_name", NewProxyResource},
+		{"nginx_config", "config_name", NewConfigResource},
+		{"nginx_Proxy", "proxy_name", NewDeprecatedProxyResource},
+		{"nginx_Config", "config_name", NewDeprecatedConfigResource},
+	}
+
+	server := newTestProviderServer(t)
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			resp, err := server.UpgradeResourceState(context.Background(), &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: tt.typeName,
+				Version:  0,
+				RawState: &tfprotov6.RawState{JSON: []byte(fmt.Sprintf(stateV0JSON, tt.nameAttribute))},
+			})
+			if err != nil {
+				t.Fatalf("UpgradeResourceState() error = %s", err)
+			}
+			if len(resp.Diagnostics) > 0 {
+				t.Fatalf("UpgradeResourceState() diagnostics = %v", resp.Diagnostics[0])
+			}
+
+			checkState(t, tt.resource(), tt.nameAttribute, resp.UpgradedState, wantState{
+				name:         "example",
+				serverName:   "example.com",
+				listenPort:   8080,
+				content:      types.StringNull(),
+				storeContent: false,
+			})
+		})
+	}
+}
+
+// TestMoveStateDeprecated moves the deprecated mixed-case resources, at
+// either schema version, onto their lowercase replacements.
+func TestMoveStateDeprecated(t *testing.T) {
+	tests := []struct {
+		source        string
+		target        string
+		nameAttribute string
+		version       int64
+		state         string
+		resource      func() resource.Resource
+		want          types.String
+		storeContent  bool
+	}{
+		{"nginx_Proxy", "nginx_proxy", "proxy_name", 0, stateV0JSON, NewProxyResource, types.StringNull(), false},
+		{"nginx_Proxy", "nginx_proxy", "proxy_name", 1, stateV1JSON, NewProxyResource, types.StringValue("server {\n\tlisten 8080;\n}\n"), true},
+		{"nginx_Config", "nginx_config", "config_name", 0, stateV0JSON, NewConfigResource, types.StringNull(), false},
+		{"nginx_Config", "nginx_config", "config_name", 1, stateV1JSON, NewConfigResource, types.StringValue("server {\n\tlisten 8080;\n}\n"), true},
+	}
+
+	server := newTestProviderServer(t)
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s v%d", tt.source, tt.version), func(t *testing.T) {
+			resp, err := server.MoveResourceState(context.Background(), &tfprotov6.MoveResourceStateRequest{
+				SourceProviderAddress: "registry.terraform.io/DBBSTech/nginx",
+				SourceTypeName:        tt.source,
+				SourceSchemaVersion:   tt.version,
+				SourceState:           &tfprotov6.RawState{JSON: []byte(fmt.Sprintf(tt.state, tt.nameAttribute))},
+				TargetTypeName:        tt.target,
+			})
+			if err != nil {
+				t.Fatalf("MoveResourceState() error = %s", err)
+			}
+			if len(resp.Diagnostics) > 0 {
+				t.Fatalf("MoveResourceState() diagnostics = %v", resp.Diagnostics[0])
+			}
+
+			checkState(t, tt.resource(), tt.nameAttribute, resp.TargetState, wantState{
+				name:         "example",
+				serverName:   "example.com",
+				listenPort:   8080,
+				content:      tt.want,
+				storeContent: tt.storeContent,
+			})
+		})
+	}
+}
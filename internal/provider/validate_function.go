@@ -0,0 +1,91 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ValidateFunction{}
+
+func NewValidateFunction() function.Function {
+	return &ValidateFunction{}
+}
+
+// ValidateFunction defines the function implementation.
+type ValidateFunction struct{}
+
+func (f *ValidateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate"
+}
+
+func (f *ValidateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Validates NGINX configuration offline",
+		MarkdownDescription: "Checks NGINX configuration against the directive catalog built into the provider, " +
+			"without connecting to a host. The catalog covers the core and the commonly built modules and knows " +
+			"where each directive is allowed and how many arguments it takes. Returns the problems found, worded " +
+			"like the errors of `nginx -t`, or an empty list. Directives from modules the catalog does not cover " +
+			"are reported as unknown.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "config",
+				MarkdownDescription: "The NGINX configuration to validate.",
+			},
+			function.StringParameter{
+				Name: "context",
+				MarkdownDescription: "The context the configuration is included in: `main`, `events`, `http`, " +
+					"`server`, `location`, `upstream` or `stream`.",
+				Validators: []function.StringParameterValidator{
+					stringvalidator.OneOf(sortedContextNames()...),
+				},
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *ValidateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var config, contextName string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &config, &contextName))
+	if resp.Error != nil {
+		return
+	}
+
+	directives, err := parseConfig(config)
+	if err != nil {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(0, err.Error()))
+		return
+	}
+
+	scope, ok := contextNames[contextName]
+	if !ok {
+		resp.Error = function.ConcatFuncErrors(resp.Error, function.NewArgumentFuncError(1, fmt.Sprintf("unknown context %q", contextName)))
+		return
+	}
+
+	problems := make([]string, 0)
+	for _, problem := range checkDirectives(directives, scope) {
+		problems = append(problems, problem.String())
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, problems))
+}
+
+func sortedContextNames() []string {
+	names := make([]string, 0, len(contextNames))
+	for name := range contextNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
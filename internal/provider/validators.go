@@ -0,0 +1,248 @@
+package nginx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.String = cidrValidator{}
+var _ validator.String = pathValidator{}
+var _ validator.String = serverNameValidator{}
+
+// configPrefixes are the directories configuration files may be written to
+// unless the provider sets config_directories.
+var configPrefixes = []string{
+	"/etc/nginx/",
+	"/usr/local/nginx/conf/",
+	"/usr/local/etc/nginx/",
+	"/opt/nginx/",
+}
+
+// readablePrefixes are the directories the nginx_file data source may read
+// from: the configuration directories and the usual locations of web roots
+// and public certificates.
+var readablePrefixes = append(append([]string{}, configPrefixes...),
+	"/usr/share/nginx/",
+	"/var/www/",
+	"/etc/ssl/certs/",
+)
+
+// hostnameLabelPattern matches a single RFC 1123 host name label.
+var hostnameLabelPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// cidrValidator checks that a string is an IP address or a CIDR network. When
+// allowKeywords is set, the special sources accepted by allow/deny ("all" and
+// "unix:") pass as well.
+type cidrValidator struct {
+	allowKeywords bool
+}
+
+func (v cidrValidator) Description(ctx context.Context) string {
+	if v.allowKeywords {
+		return "value must be an IP address, a CIDR network, \"all\" or \"unix:\""
+	}
+	return "value must be an IP address or a CIDR network"
+}
+
+func (v cidrValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v cidrValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if v.allowKeywords && (value == "all" || value == "unix:") {
+		return
+	}
+
+	if !isCIDROrIP(value) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Network Address",
+			fmt.Sprintf("%q is not a valid IP address or CIDR network: %s.", value, v.Description(ctx)),
+		)
+	}
+}
+
+func isCIDROrIP(value string) bool {
+	if net.ParseIP(value) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
+
+// pathValidator checks that a string is an absolute path without ".."
+// components. When prefixes are set, the path has to be a file below one of
+// them. Paths may contain NGINX variables.
+type pathValidator struct {
+	prefixes []string
+}
+
+func (v pathValidator) Description(ctx context.Context) string {
+	if len(v.prefixes) > 0 {
+		return "value must be an absolute file path below " + strings.Join(v.prefixes, ", ")
+	}
+	return "value must be an absolute path"
+}
+
+func (v pathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if err := v.check(value); err != "" {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Path",
+			fmt.Sprintf("%q %s: %s.", value, err, v.Description(ctx)),
+		)
+	}
+}
+
+// check returns why value is rejected, or an empty string.
+func (v pathValidator) check(value string) string {
+	if !strings.HasPrefix(value, "/") {
+		return "is not an absolute path"
+	}
+	for _, component := range strings.Split(value, "/") {
+		if component == ".." {
+			return "must not contain .. components"
+		}
+	}
+	if len(v.prefixes) == 0 {
+		return ""
+	}
+
+	if strings.HasSuffix(value, "/") {
+		return "is a directory"
+	}
+	for _, prefix := range v.prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return ""
+		}
+	}
+	return "is outside the allowed directories"
+}
+
+// checkConfigPath checks that the path planned for a configuration file is
+// below one of directories, which the resources get from the provider.
+// Schema validators cannot see the provider configuration, so this runs at
+// plan time. Nothing is checked on destroy or before the provider is
+// configured.
+func checkConfigPath(ctx context.Context, plan tfsdk.Plan, directories []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if plan.Raw.IsNull() || directories == nil {
+		return diags
+	}
+
+	var value types.String
+	diags.Append(plan.GetAttribute(ctx, path.Root("path"), &value)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	resp := validator.StringResponse{}
+	pathValidator{prefixes: directories}.ValidateString(ctx, validator.StringRequest{
+		Path:        path.Root("path"),
+		ConfigValue: value,
+	}, &resp)
+	diags.Append(resp.Diagnostics...)
+	return diags
+}
+
+// serverNameValidator checks the space-separated names of a server_name
+// directive: RFC 1123 host names, optionally with a leading or trailing
+// wildcard, IP addresses, regular expressions starting with "~", and the
+// special names "_", "" and variables such as $hostname.
+type serverNameValidator struct{}
+
+func (v serverNameValidator) Description(ctx context.Context) string {
+	return "value must be a list of host names, wildcard names such as *.example.com or www.example.*, " +
+		"IP addresses or regular expressions starting with ~"
+}
+
+func (v serverNameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v serverNameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	names := strings.Fields(req.ConfigValue.ValueString())
+	if len(names) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Server Name",
+			"The server name must not be empty: "+v.Description(ctx)+".",
+		)
+		return
+	}
+
+	for _, name := range names {
+		if !isServerName(name) {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Invalid Server Name",
+				fmt.Sprintf("%q is not a valid server name: %s.", name, v.Description(ctx)),
+			)
+		}
+	}
+}
+
+func isServerName(name string) bool {
+	switch {
+	case name == "_" || name == `""`:
+		return true
+	case strings.HasPrefix(name, "~"):
+		return len(name) > 1
+	case variableNamePattern.MatchString(name):
+		return true
+	case net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")) != nil:
+		return true
+	}
+
+	// A wildcard may only replace the first or the last label; a leading dot
+	// matches the domain and all of its subdomains
+	switch {
+	case strings.HasPrefix(name, "*."):
+		name = strings.TrimPrefix(name, "*.")
+	case strings.HasPrefix(name, "."):
+		name = strings.TrimPrefix(name, ".")
+	case strings.HasSuffix(name, ".*"):
+		name = strings.TrimSuffix(name, ".*")
+	}
+	return isHostname(name)
+}
+
+// isHostname reports whether name is an RFC 1123 host name.
+func isHostname(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !hostnameLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
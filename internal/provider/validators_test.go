@@ -0,0 +1,46 @@
+package nginx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+func TestCheckConfigPath(t *testing.T) {
+	ctx := context.Background()
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{Required: true},
+		},
+	}
+
+	tests := []struct {
+		path        string
+		directories []string
+		wantError   bool
+	}{
+		{"/etc/nginx/conf.d/example.conf", configPrefixes, false},
+		{"/srv/nginx/example.conf", configPrefixes, true},
+		{"/srv/nginx/example.conf", []string{"/srv/nginx/"}, false},
+		{"/srv/nginx-old/example.conf", []string{"/srv/nginx/"}, true},
+		{"/srv/nginx/", []string{"/srv/nginx/"}, true},
+		{"/srv/nginx/example.conf", nil, false},
+	}
+
+	for _, tt := range tests {
+		plan := tfsdk.Plan{Schema: s}
+		data := struct {
+			Path string `tfsdk:"path"`
+		}{tt.path}
+		if diags := plan.Set(ctx, &data); diags.HasError() {
+			t.Fatalf("building the plan: %v", diags)
+		}
+
+		diags := checkConfigPath(ctx, plan, tt.directories)
+		if diags.HasError() != tt.wantError {
+			t.Errorf("checkConfigPath(%q, %q) errors = %v, want error %v", tt.path, tt.directories, diags, tt.wantError)
+		}
+	}
+}